@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/brmorillo/go-lib-id/pkg/idgen"
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen"
 )
 
 // Version information (injected at build time)
@@ -2,9 +2,10 @@ package main
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
-	"github.com/brmorillo/go-lib-id/pkg/idgen"
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen"
 )
 
 // Version information (injected at build time)
@@ -121,8 +122,24 @@ func main() {
 	fmt.Printf("   📊 Efficiency: %.1f%%\n", (idsPerSecond/float64(numWorkers*4096000))*100)
 	fmt.Println()
 
-	// 4. Theoretical capacity explanation
-	fmt.Println("4️⃣  Total Theoretical Capacity:")
+	// 4. Demonstrate a shared SnowflakePool vs a single shared generator
+	fmt.Println("4️⃣  Shared generator vs SnowflakePool (1 second each):")
+
+	single, _ := idgen.New(1, 0)
+	pool, _ := idgen.NewSnowflakePool(2, 32, idgen.PoolAffinity)
+
+	for _, goroutines := range []int{1, 2, 4, 8} {
+		singleRate := measureRate(goroutines, time.Second, func() { single.Generate() })
+		poolRate := measureRate(goroutines, time.Second, func() { pool.Generate() })
+		fmt.Printf("   • %d goroutine(s): single %12.0f IDs/s | pool %12.0f IDs/s (%.1fx)\n",
+			goroutines, singleRate, poolRate, poolRate/singleRate)
+	}
+	fmt.Println("   📊 A single generator is capped at 4,096,000 IDs/second;")
+	fmt.Println("      the pool scales with its 32 workers until the CPUs are saturated.")
+	fmt.Println()
+
+	// 5. Theoretical capacity explanation
+	fmt.Println("5️⃣  Total Theoretical Capacity:")
 	fmt.Println("   ┌─────────────────────────────────────────────────┐")
 	fmt.Println("   │ Sequence: 12 bits = 4096 IDs per millisecond   │")
 	fmt.Println("   │ Workers: 32 workers × 32 processes = 1024      │")
@@ -138,8 +155,8 @@ func main() {
 	fmt.Println("   • = ~4.2 BILLION IDs per second!")
 	fmt.Println()
 
-	// 5. Scalability comparison
-	fmt.Println("5️⃣  Scalability Comparison:")
+	// 6. Scalability comparison
+	fmt.Println("6️⃣  Scalability Comparison:")
 	fmt.Println("   ┌────────────────┬──────────────────────────┐")
 	fmt.Println("   │ Configuration  │ IDs per second           │")
 	fmt.Println("   ├────────────────┼──────────────────────────┤")
@@ -150,6 +167,55 @@ func main() {
 	fmt.Println("   └────────────────┴──────────────────────────┘")
 	fmt.Println()
 
+	// 7. Layout lifetime
+	fmt.Println("7️⃣  Layout lifetime and capacity:")
+	layouts := []struct {
+		name   string
+		layout idgen.Layout
+		epoch  int64
+	}{
+		{"Default", idgen.DefaultLayout, idgen.DefaultEpoch},
+		{"Twitter", idgen.TwitterLayout, idgen.TwitterEpoch},
+		{"Discord", idgen.DiscordLayout, idgen.DiscordEpoch},
+		{"Instagram", idgen.InstagramLayout, idgen.InstagramEpoch},
+	}
+	for _, l := range layouts {
+		c, _ := l.layout.Capacity(l.epoch)
+		fmt.Printf("   • %-10s exhausted %s, %d nodes × %d IDs/s\n",
+			l.name, c.ExhaustedAt.Format("2006-01-02"), c.Nodes, c.IDsPerSecondPerWorker)
+	}
+	fmt.Printf("   • Remaining lifetime of this generator: ~%.0f years\n",
+		generator.RemainingLifetime().Hours()/24/365.25)
+	fmt.Println()
+
 	fmt.Println("✨ The limitation is NOT per second, it's per MILLISECOND!")
 	fmt.Println("   Sequence resets every millisecond, not every second.")
 }
+
+// measureRate calls generate from several goroutines for the given duration
+// and returns the combined number of calls per second
+func measureRate(goroutines int, duration time.Duration, generate func()) float64 {
+	var wg sync.WaitGroup
+	counts := make([]int, goroutines)
+
+	start := time.Now()
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			count := 0
+			for time.Since(start) < duration {
+				generate()
+				count++
+			}
+			counts[g] = count
+		}(g)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return float64(total) / time.Since(start).Seconds()
+}
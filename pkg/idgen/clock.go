@@ -0,0 +1,65 @@
+package idgen
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	reasonSequenceExhausted = "sequence exhausted"
+	reasonClockBackwards    = "clock moved backwards"
+)
+
+// sleepContext sleeps for d or until ctx is done, whichever comes first.
+// It fails immediately when the context deadline would expire before d elapses,
+// so callers never block past their budget.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	// Fast path for contexts that can never be canceled
+	done := ctx.Done()
+	if done == nil {
+		time.Sleep(d)
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// untilNextMillis returns how long to wait until the Unix millisecond after last begins
+func untilNextMillis(last int64) time.Duration {
+	return time.Until(time.UnixMilli(last + 1))
+}
+
+// Clock is the time source of a Snowflake generator.
+// Implementations must be safe for concurrent use and should never stand still
+// for long: the generator waits, in real time, for the clock to reach the next
+// millisecond when a sequence is exhausted or the clock moved backwards.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by time.Now, used by default
+type SystemClock struct{}
+
+// Now returns the current local time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
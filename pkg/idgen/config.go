@@ -0,0 +1,466 @@
+package idgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen/encoding"
+)
+
+// ErrInvalidConfig is returned when a Config cannot be loaded or validated.
+// The message names the offending field.
+var ErrInvalidConfig = errors.New("invalid generator config")
+
+// Generator kinds accepted by Config.Kind
+const (
+	KindSnowflake       = "snowflake"
+	KindAtomicSnowflake = "snowflake-atomic"
+	KindUUIDv4          = "uuidv4"
+	KindUUIDv7          = "uuidv7"
+)
+
+// Node strategies accepted by NodeConfig.Strategy
+const (
+	// NodeStatic uses NodeConfig.ProcessID and NodeConfig.WorkerID
+	NodeStatic = "static"
+
+	// NodeEnv reads the IDs from EnvProcessID and EnvWorkerID, or EnvNodeID, like SnowflakeFromEnv,
+	// but checks them against the configured layout and splits EnvNodeID at its worker ID bits
+	NodeEnv = "env"
+
+	// NodeOrdinal derives a node ID (0-1023) from the number at the end of the host name,
+	// e.g. orders-7 in a Kubernetes StatefulSet
+	NodeOrdinal = "ordinal"
+)
+
+// Config describes a generator declaratively, so it can be loaded from JSON, YAML
+// or environment variables and turned into a generator with Build.
+// Empty fields take their defaults.
+//
+// Example (YAML):
+//
+//	kind: snowflake
+//	layout: discord       # preset name or "timestamp/process/worker/sequence" bits, e.g. 41/5/5/12
+//	epoch: discord        # preset name, milliseconds since Unix epoch, or RFC 3339
+//	node:
+//	  strategy: ordinal
+//	encoding: base58
+//	clock_policy: fail
+type Config struct {
+	// Kind is the generator kind: snowflake (default), snowflake-atomic, uuidv4 or uuidv7
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+
+	// Layout is the Snowflake bit layout: default, twitter, discord, instagram, mastodon,
+	// or explicit bits such as "41/5/5/12". Only snowflake supports non-default layouts.
+	Layout string `json:"layout,omitempty" yaml:"layout,omitempty"`
+
+	// Epoch is the Snowflake epoch: a preset name (default, twitter, discord, instagram,
+	// mastodon, unix), milliseconds since Unix epoch, or an RFC 3339 time.
+	// It defaults to the epoch of the layout preset, or DefaultEpoch.
+	Epoch string `json:"epoch,omitempty" yaml:"epoch,omitempty"`
+
+	// Node selects the process and worker IDs of Snowflake generators
+	Node NodeConfig `json:"node,omitempty" yaml:"node,omitempty"`
+
+	// Encoding is the text form returned by GenerateString: canonical (default; decimal or
+	// the UUID form), crockford32, base58, base58-flickr, base62 or base64url
+	Encoding string `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+
+	// ClockPolicy is wait (default) or fail; see ClockPolicy. Only snowflake supports fail.
+	ClockPolicy string `json:"clock_policy,omitempty" yaml:"clock_policy,omitempty"`
+}
+
+// NodeConfig selects the process and worker IDs of a Snowflake generator
+type NodeConfig struct {
+	// Strategy is static (default), env or ordinal
+	Strategy string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+
+	// ProcessID is used by the static strategy
+	ProcessID int64 `json:"process_id,omitempty" yaml:"process_id,omitempty"`
+
+	// WorkerID is used by the static strategy
+	WorkerID int64 `json:"worker_id,omitempty" yaml:"worker_id,omitempty"`
+}
+
+// layoutPresets maps layout names to layouts and their default epochs
+var layoutPresets = map[string]struct {
+	layout Layout
+	epoch  int64
+}{
+	"default":   {DefaultLayout, DefaultEpoch},
+	"twitter":   {TwitterLayout, TwitterEpoch},
+	"discord":   {DiscordLayout, DiscordEpoch},
+	"instagram": {InstagramLayout, InstagramEpoch},
+	"mastodon":  {MastodonLayout, MastodonEpoch},
+}
+
+// epochPresets maps epoch names to epochs
+var epochPresets = map[string]int64{
+	"default":   DefaultEpoch,
+	"twitter":   TwitterEpoch,
+	"discord":   DiscordEpoch,
+	"instagram": InstagramEpoch,
+	"mastodon":  MastodonEpoch,
+	"unix":      0,
+}
+
+// encodingsByName maps Config.Encoding values to encodings; canonical maps to nil
+var encodingsByName = map[string]encoding.Encoding{
+	"canonical":     nil,
+	"crockford32":   encoding.Crockford32,
+	"base58":        encoding.Base58Bitcoin,
+	"base58-flickr": encoding.Base58Flickr,
+	"base62":        encoding.Base62,
+	"base64url":     encoding.Base64URL,
+}
+
+// ParseConfigJSON decodes a Config from JSON and validates it.
+// Unknown fields are rejected, so typos are reported instead of ignored.
+//
+// Returns:
+//   - Config: The decoded config
+//   - error: ErrInvalidConfig describing the first problem found
+func ParseConfigJSON(data []byte) (Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// ConfigFromEnv reads a Config from environment variables named prefix followed by
+// KIND, LAYOUT, EPOCH, NODE_STRATEGY, PROCESS_ID, WORKER_ID, ENCODING and CLOCK_POLICY,
+// e.g. IDGEN_KIND with the prefix "IDGEN_". Unset variables keep their defaults.
+//
+// Returns:
+//   - Config: The config read from the environment
+//   - error: ErrInvalidConfig describing the first problem found
+func ConfigFromEnv(prefix string) (Config, error) {
+	cfg := Config{
+		Kind:        os.Getenv(prefix + "KIND"),
+		Layout:      os.Getenv(prefix + "LAYOUT"),
+		Epoch:       os.Getenv(prefix + "EPOCH"),
+		Encoding:    os.Getenv(prefix + "ENCODING"),
+		ClockPolicy: os.Getenv(prefix + "CLOCK_POLICY"),
+		Node:        NodeConfig{Strategy: os.Getenv(prefix + "NODE_STRATEGY")},
+	}
+
+	for _, field := range []struct {
+		key string
+		dst *int64
+	}{
+		{prefix + "PROCESS_ID", &cfg.Node.ProcessID},
+		{prefix + "WORKER_ID", &cfg.Node.WorkerID},
+	} {
+		v, ok := os.LookupEnv(field.key)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("%w: %s=%q is not an integer", ErrInvalidConfig, field.key, v)
+		}
+		*field.dst = n
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks every field of the config without creating a generator.
+// Node IDs of the env and ordinal strategies are only checked by Build.
+//
+// Returns:
+//   - error: ErrInvalidConfig describing the first problem found
+func (c Config) Validate() error {
+	_, err := c.resolve()
+	return err
+}
+
+// resolvedConfig holds a validated Config with defaults applied
+type resolvedConfig struct {
+	kind        string
+	layout      Layout
+	epoch       int64
+	encoding    encoding.Encoding
+	clockPolicy ClockPolicy
+}
+
+// resolve validates the config and applies the defaults
+func (c Config) resolve() (resolvedConfig, error) {
+	r := resolvedConfig{kind: c.Kind, layout: DefaultLayout, epoch: DefaultEpoch}
+	if r.kind == "" {
+		r.kind = KindSnowflake
+	}
+	snowflake := r.kind == KindSnowflake || r.kind == KindAtomicSnowflake
+	switch r.kind {
+	case KindSnowflake, KindAtomicSnowflake, KindUUIDv4, KindUUIDv7:
+	default:
+		return r, configError("kind", c.Kind, "must be snowflake, snowflake-atomic, uuidv4 or uuidv7")
+	}
+
+	if c.Layout != "" {
+		if !snowflake {
+			return r, configError("layout", c.Layout, "is only used by snowflake kinds")
+		}
+		if preset, ok := layoutPresets[strings.ToLower(c.Layout)]; ok {
+			r.layout, r.epoch = preset.layout, preset.epoch
+		} else {
+			layout, err := parseLayoutBits(c.Layout)
+			if err != nil {
+				return r, fmt.Errorf("%w: layout %q: %w", ErrInvalidConfig, c.Layout, err)
+			}
+			r.layout = layout
+		}
+		if r.kind == KindAtomicSnowflake && r.layout != DefaultLayout {
+			return r, configError("layout", c.Layout, "is not supported by snowflake-atomic, which uses DefaultLayout")
+		}
+	}
+
+	if c.Epoch != "" {
+		if !snowflake {
+			return r, configError("epoch", c.Epoch, "is only used by snowflake kinds")
+		}
+		epoch, err := parseEpoch(c.Epoch)
+		if err != nil {
+			return r, configError("epoch", c.Epoch, "must be a preset name, milliseconds since Unix epoch, or RFC 3339")
+		}
+		if epoch > time.Now().UnixMilli() {
+			return r, configError("epoch", c.Epoch, "must not be in the future")
+		}
+		r.epoch = epoch
+	}
+
+	switch c.Node.Strategy {
+	case "", NodeStatic, NodeEnv, NodeOrdinal:
+	default:
+		return r, configError("node.strategy", c.Node.Strategy, "must be static, env or ordinal")
+	}
+	if !snowflake && (c.Node != NodeConfig{}) {
+		return r, fmt.Errorf("%w: node is only used by snowflake kinds", ErrInvalidConfig)
+	}
+	if snowflake && c.Node.Strategy != NodeEnv && c.Node.Strategy != NodeOrdinal {
+		if c.Node.ProcessID < 0 || c.Node.ProcessID > r.layout.MaxProcessID() {
+			return r, configError("node.process_id", strconv.FormatInt(c.Node.ProcessID, 10),
+				fmt.Sprintf("must be between 0 and %d", r.layout.MaxProcessID()))
+		}
+		if c.Node.WorkerID < 0 || c.Node.WorkerID > r.layout.MaxWorkerID() {
+			return r, configError("node.worker_id", strconv.FormatInt(c.Node.WorkerID, 10),
+				fmt.Sprintf("must be between 0 and %d", r.layout.MaxWorkerID()))
+		}
+	}
+
+	if c.Encoding != "" {
+		enc, ok := encodingsByName[strings.ToLower(c.Encoding)]
+		if !ok {
+			return r, configError("encoding", c.Encoding, "must be canonical, crockford32, base58, base58-flickr, base62 or base64url")
+		}
+		r.encoding = enc
+	}
+
+	switch c.ClockPolicy {
+	case "", ClockWait.String():
+	case ClockFail.String():
+		if r.kind != KindSnowflake {
+			return r, configError("clock_policy", c.ClockPolicy, "is only supported by kind snowflake")
+		}
+		r.clockPolicy = ClockFail
+	default:
+		return r, configError("clock_policy", c.ClockPolicy, "must be wait or fail")
+	}
+
+	return r, nil
+}
+
+// configError reports an invalid config field
+func configError(field, value, problem string) error {
+	return fmt.Errorf("%w: %s %q %s", ErrInvalidConfig, field, value, problem)
+}
+
+// parseLayoutBits parses a layout written as "timestamp/process/worker/sequence" bits
+func parseLayoutBits(s string) (Layout, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 4 {
+		return Layout{}, errors.New("must be a preset name or timestamp/process/worker/sequence bits")
+	}
+
+	var bits [4]uint
+	for i, part := range parts {
+		n, err := strconv.ParseUint(strings.TrimSpace(part), 10, 8)
+		if err != nil {
+			return Layout{}, fmt.Errorf("bit count %q is not a number", part)
+		}
+		bits[i] = uint(n)
+	}
+
+	layout := Layout{TimestampBits: bits[0], ProcessIDBits: bits[1], WorkerIDBits: bits[2], SequenceBits: bits[3]}
+	return layout, layout.Validate()
+}
+
+// parseEpoch parses an epoch preset name, milliseconds since Unix epoch, or an RFC 3339 time
+func parseEpoch(s string) (int64, error) {
+	if epoch, ok := epochPresets[strings.ToLower(s)]; ok {
+		return epoch, nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ms, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixMilli(), nil
+}
+
+// ordinalFromHostname returns the number at the end of a host name such as orders-7
+func ordinalFromHostname(hostname string) (int64, error) {
+	i := len(hostname)
+	for i > 0 && hostname[i-1] >= '0' && hostname[i-1] <= '9' {
+		i--
+	}
+	if i == len(hostname) {
+		return 0, fmt.Errorf("host name %q does not end with an ordinal", hostname)
+	}
+	return strconv.ParseInt(hostname[i:], 10, 64)
+}
+
+// nodeIDs returns the process and worker IDs selected by the node strategy
+func (c Config) nodeIDs(layout Layout) (processID, workerID int64, err error) {
+	switch c.Node.Strategy {
+	case NodeEnv:
+		return nodeFromEnv(layout)
+	case NodeOrdinal:
+		hostname, err := os.Hostname()
+		if err != nil {
+			return 0, 0, err
+		}
+		ordinal, err := ordinalFromHostname(hostname)
+		if err != nil {
+			return 0, 0, err
+		}
+		workerBits := layout.WorkerIDBits
+		if ordinal > (layout.MaxProcessID()+1)<<workerBits-1 {
+			return 0, 0, fmt.Errorf("ordinal %d of host name %q exceeds the node range of the layout", ordinal, hostname)
+		}
+		return ordinal >> workerBits, ordinal & layout.MaxWorkerID(), nil
+	}
+	return c.Node.ProcessID, c.Node.WorkerID, nil
+}
+
+// Build validates cfg and returns the configured generator.
+// With the canonical encoding the concrete generator is returned, so it can be
+// type-asserted, e.g. to *Snowflake; other encodings wrap it.
+//
+// Returns:
+//   - Generator: The configured generator
+//   - error: ErrInvalidConfig describing the first problem found
+//
+// Example:
+//
+//	cfg, err := idgen.ParseConfigJSON(data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	generator, err := idgen.Build(cfg)
+//	id, err := generator.GenerateString(ctx)
+func Build(cfg Config) (Generator, error) {
+	r, err := cfg.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	switch r.kind {
+	case KindUUIDv4:
+		g := NewUUIDv4Generator()
+		if r.encoding == nil {
+			return g, nil
+		}
+		return encodedUUIDGenerator{enc: r.encoding, generate: func(ctx context.Context) (UUID, error) {
+			if err := ctx.Err(); err != nil {
+				return UUID{}, err
+			}
+			return g.Generate()
+		}}, nil
+
+	case KindUUIDv7:
+		g := &UUIDv7Generator{}
+		if r.encoding == nil {
+			return g, nil
+		}
+		return encodedUUIDGenerator{enc: r.encoding, generate: g.GenerateContext}, nil
+	}
+
+	processID, workerID, err := cfg.nodeIDs(r.layout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: node: %w", ErrInvalidConfig, err)
+	}
+
+	var g interface {
+		Generator
+		GenerateContext(ctx context.Context) (int64, error)
+	}
+	if r.kind == KindAtomicSnowflake {
+		g, err = NewAtomicWithEpoch(processID, workerID, r.epoch)
+	} else {
+		var s *Snowflake
+		s, err = NewSnowflakeGenerator(
+			WithNode(processID, workerID),
+			WithLayout(r.layout),
+			WithEpoch(r.epoch),
+			WithClockPolicy(r.clockPolicy),
+		)
+		g = s
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: node: %w", ErrInvalidConfig, err)
+	}
+
+	if r.encoding == nil {
+		return g, nil
+	}
+	return encodedSnowflakeGenerator{enc: r.encoding, generate: g.GenerateContext}, nil
+}
+
+// encodedSnowflakeGenerator returns Snowflake IDs in an alternative encoding
+type encodedSnowflakeGenerator struct {
+	enc      encoding.Encoding
+	generate func(ctx context.Context) (int64, error)
+}
+
+// GenerateString creates a new Snowflake ID and encodes it
+func (g encodedSnowflakeGenerator) GenerateString(ctx context.Context) (string, error) {
+	id, err := g.generate(ctx)
+	if err != nil {
+		return "", err
+	}
+	return FormatSnowflake(id, g.enc), nil
+}
+
+// encodedUUIDGenerator returns UUIDs in an alternative encoding
+type encodedUUIDGenerator struct {
+	enc      encoding.Encoding
+	generate func(ctx context.Context) (UUID, error)
+}
+
+// GenerateString creates a new UUID and encodes it
+func (g encodedUUIDGenerator) GenerateString(ctx context.Context) (string, error) {
+	uuid, err := g.generate(ctx)
+	if err != nil {
+		return "", err
+	}
+	return uuid.Encode(g.enc), nil
+}
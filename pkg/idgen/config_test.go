@@ -0,0 +1,233 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen/encoding"
+)
+
+func TestParseConfigJSON(t *testing.T) {
+	cfg, err := ParseConfigJSON([]byte(`{
+		"kind": "snowflake",
+		"layout": "discord",
+		"node": {"strategy": "static", "process_id": 3, "worker_id": 4},
+		"encoding": "base58",
+		"clock_policy": "fail"
+	}`))
+	if err != nil {
+		t.Fatalf("ParseConfigJSON() error = %v", err)
+	}
+	want := Config{
+		Kind:        KindSnowflake,
+		Layout:      "discord",
+		Node:        NodeConfig{Strategy: NodeStatic, ProcessID: 3, WorkerID: 4},
+		Encoding:    "base58",
+		ClockPolicy: "fail",
+	}
+	if cfg != want {
+		t.Errorf("ParseConfigJSON() = %+v, want %+v", cfg, want)
+	}
+
+	if _, err := ParseConfigJSON([]byte(`{"kind": "snowflake", "procss_id": 3}`)); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for an unknown field, got %v", err)
+	}
+	if _, err := ParseConfigJSON([]byte(`{"kind": "snowflake", "layout": "41/5/5"}`)); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for a bad layout, got %v", err)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"zero value", Config{}, ""},
+		{"uuidv7", Config{Kind: KindUUIDv7, Encoding: "crockford32"}, ""},
+		{"explicit layout", Config{Layout: "42/4/4/12", Epoch: "2024-01-01T00:00:00Z"}, ""},
+		{"epoch in milliseconds", Config{Epoch: "1420070400000"}, ""},
+		{"unknown kind", Config{Kind: "ulid"}, "kind"},
+		{"unknown layout", Config{Layout: "facebook"}, "layout"},
+		{"invalid layout bits", Config{Layout: "50/5/5/12"}, "layout"},
+		{"invalid epoch", Config{Epoch: "yesterday"}, "epoch"},
+		{"future epoch", Config{Epoch: time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)}, "epoch"},
+		{"process out of range", Config{Node: NodeConfig{ProcessID: 32}}, "node.process_id"},
+		{"worker out of layout range", Config{Layout: "42/4/4/12", Node: NodeConfig{WorkerID: 16}}, "node.worker_id"},
+		{"unknown strategy", Config{Node: NodeConfig{Strategy: "random"}}, "node.strategy"},
+		{"unknown encoding", Config{Encoding: "base36"}, "encoding"},
+		{"unknown clock policy", Config{ClockPolicy: "ignore"}, "clock_policy"},
+		{"layout on uuid", Config{Kind: KindUUIDv4, Layout: "default"}, "layout"},
+		{"node on uuid", Config{Kind: KindUUIDv7, Node: NodeConfig{WorkerID: 1}}, "node"},
+		{"clock policy on atomic", Config{Kind: KindAtomicSnowflake, ClockPolicy: "fail"}, "clock_policy"},
+		{"layout on atomic", Config{Kind: KindAtomicSnowflake, Layout: "discord"}, "layout"},
+		{"default-equivalent layout on atomic", Config{Kind: KindAtomicSnowflake, Layout: "twitter"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want ErrInvalidConfig mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("APP_ID_KIND", "snowflake")
+	t.Setenv("APP_ID_LAYOUT", "twitter")
+	t.Setenv("APP_ID_PROCESS_ID", "2")
+	t.Setenv("APP_ID_WORKER_ID", "9")
+	t.Setenv("APP_ID_ENCODING", "crockford32")
+
+	cfg, err := ConfigFromEnv("APP_ID_")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv() error = %v", err)
+	}
+	want := Config{Kind: KindSnowflake, Layout: "twitter", Encoding: "crockford32", Node: NodeConfig{ProcessID: 2, WorkerID: 9}}
+	if cfg != want {
+		t.Errorf("ConfigFromEnv() = %+v, want %+v", cfg, want)
+	}
+
+	t.Setenv("APP_ID_WORKER_ID", "nine")
+	if _, err := ConfigFromEnv("APP_ID_"); !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "APP_ID_WORKER_ID") {
+		t.Errorf("Expected ErrInvalidConfig naming APP_ID_WORKER_ID, got %v", err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+
+	g, err := Build(Config{Layout: "twitter", Node: NodeConfig{ProcessID: 1, WorkerID: 2}, ClockPolicy: "fail"})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	snowflake, ok := g.(*Snowflake)
+	if !ok {
+		t.Fatalf("Build() = %T, want *Snowflake", g)
+	}
+	if snowflake.Layout() != TwitterLayout || snowflake.Epoch() != TwitterEpoch || snowflake.ClockPolicy() != ClockFail {
+		t.Errorf("Build() = layout %+v, epoch %d, policy %v", snowflake.Layout(), snowflake.Epoch(), snowflake.ClockPolicy())
+	}
+	s, err := g.GenerateString(ctx)
+	if err != nil {
+		t.Fatalf("GenerateString() error = %v", err)
+	}
+	id, _ := strconv.ParseInt(s, 10, 64)
+	if snowflake.ExtractProcessID(id) != 1 || snowflake.ExtractWorkerID(id) != 2 {
+		t.Errorf("GenerateString() = %s has the wrong node", s)
+	}
+
+	g, err = Build(Config{Kind: KindAtomicSnowflake, Encoding: "base58"})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	s, _ = g.GenerateString(ctx)
+	if _, err := ParseSnowflakeWith(encoding.Base58Bitcoin, s); err != nil {
+		t.Errorf("GenerateString() = %q is not a Base58 Snowflake ID: %v", s, err)
+	}
+
+	for _, kind := range []string{KindUUIDv4, KindUUIDv7} {
+		g, err = Build(Config{Kind: kind})
+		if err != nil {
+			t.Fatalf("Build(%s) error = %v", kind, err)
+		}
+		s, _ = g.GenerateString(ctx)
+		if _, err := ParseUUID(s); err != nil {
+			t.Errorf("Build(%s).GenerateString() = %q is not a UUID", kind, s)
+		}
+
+		g, _ = Build(Config{Kind: kind, Encoding: "base62"})
+		s, _ = g.GenerateString(ctx)
+		if _, err := ParseUUIDWith(encoding.Base62, s); err != nil {
+			t.Errorf("Build(%s, base62).GenerateString() = %q: %v", kind, s, err)
+		}
+	}
+
+	if _, err := Build(Config{Kind: "bogus"}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestBuildNodeStrategies(t *testing.T) {
+	t.Setenv(EnvProcessID, "")
+	t.Setenv(EnvWorkerID, "")
+	unsetEnv(t, EnvProcessID)
+	unsetEnv(t, EnvWorkerID)
+	t.Setenv(EnvNodeID, "70")
+
+	g, err := Build(Config{Node: NodeConfig{Strategy: NodeEnv}})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if s := g.(*Snowflake); s.ProcessID() != 2 || s.WorkerID() != 6 {
+		t.Errorf("Build() node = %d/%d, want 2/6", s.ProcessID(), s.WorkerID())
+	}
+
+	// Instagram has 13 process ID bits and no worker ID bits
+	t.Setenv(EnvNodeID, "5")
+	g, err = Build(Config{Layout: "instagram", Node: NodeConfig{Strategy: NodeEnv}})
+	if err != nil {
+		t.Fatalf("Build(instagram) with %s error = %v", EnvNodeID, err)
+	}
+	if s := g.(*Snowflake); s.ProcessID() != 5 || s.WorkerID() != 0 {
+		t.Errorf("Build(instagram) node = %d/%d, want 5/0", s.ProcessID(), s.WorkerID())
+	}
+	t.Setenv(EnvNodeID, "8192")
+	if _, err := Build(Config{Layout: "instagram", Node: NodeConfig{Strategy: NodeEnv}}); !errors.Is(err, ErrInvalidNodeID) {
+		t.Errorf("Build(instagram) with node 8192 error = %v, want ErrInvalidNodeID", err)
+	}
+
+	unsetEnv(t, EnvNodeID)
+	t.Setenv(EnvProcessID, "1000")
+	t.Setenv(EnvWorkerID, "0")
+	g, err = Build(Config{Layout: "instagram", Node: NodeConfig{Strategy: NodeEnv}})
+	if err != nil {
+		t.Fatalf("Build(instagram) with %s error = %v", EnvProcessID, err)
+	}
+	if s := g.(*Snowflake); s.ProcessID() != 1000 || s.WorkerID() != 0 {
+		t.Errorf("Build(instagram) node = %d/%d, want 1000/0", s.ProcessID(), s.WorkerID())
+	}
+	t.Setenv(EnvWorkerID, "1")
+	if _, err := Build(Config{Layout: "instagram", Node: NodeConfig{Strategy: NodeEnv}}); !errors.Is(err, ErrInvalidWorkerID) {
+		t.Errorf("Build(instagram) with worker 1 error = %v, want ErrInvalidWorkerID", err)
+	}
+
+	unsetEnv(t, EnvProcessID)
+	unsetEnv(t, EnvWorkerID)
+	if _, err := Build(Config{Node: NodeConfig{Strategy: NodeEnv}}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig without environment, got %v", err)
+	}
+}
+
+func TestOrdinalFromHostname(t *testing.T) {
+	tests := []struct {
+		hostname string
+		want     int64
+		wantErr  bool
+	}{
+		{"orders-7", 7, false},
+		{"orders-1023", 1023, false},
+		{"web12", 12, false},
+		{"orders", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ordinalFromHostname(tt.hostname)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ordinalFromHostname(%q) = %d, %v", tt.hostname, got, err)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned when encoded text is well-formed but its check symbol is wrong,
+// typically because of a typo
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Checksum computes a single check symbol over encoded text, to detect typos
+// when IDs are read aloud or typed by hand
+type Checksum interface {
+	// CheckSymbol returns the check symbol for text.
+	// It returns ErrInvalidCharacter if text contains characters the checksum does not cover.
+	CheckSymbol(text []byte) (byte, error)
+
+	// Verify checks that symbol is the check symbol of text.
+	// It returns ErrChecksumMismatch if it is not, or ErrInvalidCharacter for characters
+	// the checksum does not cover.
+	Verify(text []byte, symbol byte) error
+}
+
+// Damm is the Damm algorithm over decimal digits. It detects all single-digit errors
+// and all adjacent transpositions.
+var Damm Checksum = damm{}
+
+// CrockfordMod37 is Crockford's Base32 check symbol: the encoded value modulo 37,
+// written with the extra symbols *, ~, $, = and U. Text and symbol are read
+// case-insensitively, with the same aliases as Crockford32.
+var CrockfordMod37 Checksum = crockfordMod37{}
+
+// LuhnDecimal is the classic Luhn check digit over decimal digits
+var LuhnDecimal = NewLuhn(decimalDigits)
+
+// decimalDigits is the symbol set of the decimal checksums
+const decimalDigits = "0123456789"
+
+// dammTable is the totally anti-symmetric quasigroup of order 10 used by Damm
+var dammTable = [10][10]byte{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// damm implements the Damm algorithm
+type damm struct{}
+
+// CheckSymbol returns the Damm check digit of text
+func (damm) CheckSymbol(text []byte) (byte, error) {
+	var interim byte
+	for i, c := range text {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%w %q at position %d", ErrInvalidCharacter, c, i)
+		}
+		interim = dammTable[interim][c-'0']
+	}
+	return '0' + interim, nil
+}
+
+// Verify checks the Damm check digit of text
+func (d damm) Verify(text []byte, symbol byte) error {
+	return verifySymbol(d, text, symbol, decimalDigits, func(c byte) byte { return c })
+}
+
+// Luhn is the Luhn mod N algorithm over an alphabet of N characters.
+// It detects all single-character errors and most adjacent transpositions.
+type Luhn struct {
+	alphabet  string
+	decodeMap [256]int16
+}
+
+// NewLuhn creates a Luhn mod N checksum over alphabet, where N is the alphabet length.
+// Use the alphabet of the encoding the checksum protects, e.g. Base58BitcoinAlphabet.
+// It panics if the alphabet has fewer than 2 or more than 256 characters or repeats a character.
+func NewLuhn(alphabet string) *Luhn {
+	r := NewRadix(alphabet)
+	return &Luhn{alphabet: alphabet, decodeMap: r.decodeMap}
+}
+
+// CheckSymbol returns the Luhn mod N check character of text
+func (l *Luhn) CheckSymbol(text []byte) (byte, error) {
+	n := len(l.alphabet)
+	factor, sum := 2, 0
+	for i := len(text) - 1; i >= 0; i-- {
+		code := l.decodeMap[text[i]]
+		if code < 0 {
+			return 0, fmt.Errorf("%w %q at position %d", ErrInvalidCharacter, text[i], i)
+		}
+		addend := factor * int(code)
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return l.alphabet[(n-sum%n)%n], nil
+}
+
+// Verify checks the Luhn mod N check character of text
+func (l *Luhn) Verify(text []byte, symbol byte) error {
+	return verifySymbol(l, text, symbol, l.alphabet, func(c byte) byte { return c })
+}
+
+// crockfordCheckSymbols are the Crockford Base32 digits followed by the 5 extra check symbols
+const crockfordCheckSymbols = Crockford32Alphabet + "*~$=U"
+
+// crockfordMod37 implements Crockford's check symbol
+type crockfordMod37 struct{}
+
+// CheckSymbol returns the Crockford check symbol of text
+func (crockfordMod37) CheckSymbol(text []byte) (byte, error) {
+	lenient := &Crockford32.(*crockford32).lenient
+	remainder := 0
+	for i, c := range text {
+		digit := lenient[c]
+		if digit < 0 {
+			return 0, fmt.Errorf("%w %q at position %d", ErrInvalidCharacter, c, i)
+		}
+		remainder = (remainder*32 + int(digit)) % 37
+	}
+	return crockfordCheckSymbols[remainder], nil
+}
+
+// Verify checks the Crockford check symbol of text, case-insensitively
+func (c crockfordMod37) Verify(text []byte, symbol byte) error {
+	return verifySymbol(c, text, symbol, crockfordCheckSymbols, normalizeCrockfordSymbol)
+}
+
+// normalizeCrockfordSymbol maps a check symbol to its canonical form
+func normalizeCrockfordSymbol(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'O':
+		return '0'
+	case 'I', 'L':
+		return '1'
+	}
+	return c
+}
+
+// verifySymbol compares symbol with the check symbol of text after normalizing it.
+// A symbol outside symbols is malformed rather than a checksum mismatch.
+func verifySymbol(cs Checksum, text []byte, symbol byte, symbols string, normalize func(byte) byte) error {
+	want, err := cs.CheckSymbol(text)
+	if err != nil {
+		return err
+	}
+	if strings.IndexByte(symbols, normalize(symbol)) < 0 {
+		return fmt.Errorf("%w %q in check symbol", ErrInvalidCharacter, symbol)
+	}
+	if normalize(symbol) != want {
+		return fmt.Errorf("%w: check symbol %q, want %q", ErrChecksumMismatch, symbol, want)
+	}
+	return nil
+}
+
+// checked appends a check symbol to another encoding
+type checked struct {
+	enc Encoding
+	cs  Checksum
+}
+
+// WithChecksum returns an encoding that appends the check symbol of cs to the output of enc,
+// and verifies it when decoding. cs must cover the alphabet of enc, e.g.
+// WithChecksum(Crockford32, CrockfordMod37) or WithChecksum(Base58Bitcoin, NewLuhn(Base58BitcoinAlphabet)).
+//
+// Decode reports malformed text with errors wrapping ErrMalformed and typos caught by
+// the checksum with ErrChecksumMismatch.
+//
+// Example:
+//
+//	enc := encoding.WithChecksum(encoding.Crockford32, encoding.CrockfordMod37)
+//	s := uuid.Encode(enc) // 27 characters: 26 Base32 digits and a check symbol
+//	_, err := idgen.ParseUUIDWith(enc, s)
+//	if errors.Is(err, encoding.ErrChecksumMismatch) {
+//	    // ask the user to re-read the ID
+//	}
+func WithChecksum(enc Encoding, cs Checksum) Encoding {
+	return checked{enc: enc, cs: cs}
+}
+
+// EncodedLen returns the encoded length of n bytes, including the check symbol
+func (c checked) EncodedLen(n int) int {
+	return c.enc.EncodedLen(n) + 1
+}
+
+// DecodedLen returns the number of bytes decoded from n characters, including the check symbol
+func (c checked) DecodedLen(n int) int {
+	if n < 1 {
+		return 0
+	}
+	return c.enc.DecodedLen(n - 1)
+}
+
+// Encode writes the encoding of src followed by its check symbol.
+// It panics if the checksum does not cover the alphabet of the encoding.
+func (c checked) Encode(dst, src []byte) {
+	n := len(dst) - 1
+	c.enc.Encode(dst[:n], src)
+	symbol, err := c.cs.CheckSymbol(dst[:n])
+	if err != nil {
+		panic("encoding: checksum does not cover the encoding alphabet: " + err.Error())
+	}
+	dst[n] = symbol
+}
+
+// Decode verifies the check symbol and decodes src into dst
+func (c checked) Decode(dst, src []byte) error {
+	if want := c.EncodedLen(len(dst)); len(src) != want {
+		return fmt.Errorf("%w: got %d characters, want %d", ErrInvalidLength, len(src), want)
+	}
+
+	n := len(src) - 1
+	if err := c.enc.Decode(dst, src[:n]); err != nil {
+		return err
+	}
+	if err := c.cs.Verify(src[:n], src[n]); err != nil {
+		clear(dst)
+		return err
+	}
+	return nil
+}
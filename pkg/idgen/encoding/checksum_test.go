@@ -0,0 +1,225 @@
+package encoding
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestChecksumVectors(t *testing.T) {
+	tests := []struct {
+		name string
+		cs   Checksum
+		text string
+		want byte
+	}{
+		{"damm", Damm, "572", '4'},
+		{"damm empty", Damm, "", '0'},
+		{"luhn decimal", LuhnDecimal, "7992739871", '3'},
+		{"luhn decimal zero", LuhnDecimal, "0", '0'},
+		{"luhn hex", NewLuhn("0123456789abcdef"), "1a", 'a'},
+		{"crockford zero", CrockfordMod37, "0", '0'},
+		{"crockford 31", CrockfordMod37, "Z", 'Z'},
+		{"crockford 32", CrockfordMod37, "10", '*'},
+		{"crockford 36", CrockfordMod37, "14", 'U'},
+		{"crockford 37", CrockfordMod37, "15", '0'},
+		{"crockford 33", CrockfordMod37, "11", '~'},
+		{"crockford lowercase and aliases", CrockfordMod37, "il", '~'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cs.CheckSymbol([]byte(tt.text))
+			if err != nil {
+				t.Fatalf("CheckSymbol() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckSymbol() = %q, want %q", got, tt.want)
+			}
+			if err := tt.cs.Verify([]byte(tt.text), tt.want); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestChecksumDetectsTypos(t *testing.T) {
+	tests := []struct {
+		name     string
+		cs       Checksum
+		alphabet string
+	}{
+		{"damm", Damm, "0123456789"},
+		{"luhn decimal", LuhnDecimal, "0123456789"},
+		{"luhn base58", NewLuhn(Base58BitcoinAlphabet), Base58BitcoinAlphabet},
+		{"crockford", CrockfordMod37, Crockford32Alphabet},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 200; i++ {
+				text := make([]byte, 12)
+				for j := range text {
+					text[j] = tt.alphabet[rng.Intn(len(tt.alphabet))]
+				}
+				symbol, err := tt.cs.CheckSymbol(text)
+				if err != nil {
+					t.Fatalf("CheckSymbol(%q) error = %v", text, err)
+				}
+
+				// Every single-character substitution is detected
+				pos := rng.Intn(len(text))
+				typo := bytes.Clone(text)
+				for typo[pos] == text[pos] {
+					typo[pos] = tt.alphabet[rng.Intn(len(tt.alphabet))]
+				}
+				if err := tt.cs.Verify(typo, symbol); !errors.Is(err, ErrChecksumMismatch) {
+					t.Fatalf("Verify(%q) with typo at %d: expected ErrChecksumMismatch, got %v", typo, pos, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDammDetectsTranspositions(t *testing.T) {
+	text := []byte("175928847299117063")
+	symbol, _ := Damm.CheckSymbol(text)
+	for i := 0; i+1 < len(text); i++ {
+		if text[i] == text[i+1] {
+			continue
+		}
+		swapped := bytes.Clone(text)
+		swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+		if err := Damm.Verify(swapped, symbol); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Transposition at %d not detected: %v", i, err)
+		}
+	}
+}
+
+func TestChecksumInvalidCharacter(t *testing.T) {
+	tests := []struct {
+		name string
+		cs   Checksum
+		text string
+	}{
+		{"damm letter", Damm, "12a"},
+		{"luhn base58 zero", NewLuhn(Base58BitcoinAlphabet), "10"},
+		{"crockford U", CrockfordMod37, "1U"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cs.CheckSymbol([]byte(tt.text)); !errors.Is(err, ErrInvalidCharacter) {
+				t.Errorf("CheckSymbol() expected ErrInvalidCharacter, got %v", err)
+			}
+			if err := tt.cs.Verify([]byte(tt.text), '0'); !errors.Is(err, ErrMalformed) {
+				t.Errorf("Verify() expected ErrMalformed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestChecksumInvalidSymbol(t *testing.T) {
+	tests := []struct {
+		name   string
+		cs     Checksum
+		symbol byte
+	}{
+		{"damm", Damm, '#'},
+		{"luhn decimal", LuhnDecimal, 'a'},
+		{"luhn base58 zero", NewLuhn(Base58BitcoinAlphabet), '0'},
+		{"crockford", CrockfordMod37, '#'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cs.Verify([]byte("12"), tt.symbol)
+			if !errors.Is(err, ErrInvalidCharacter) || errors.Is(err, ErrChecksumMismatch) {
+				t.Errorf("Verify() with symbol %q expected only ErrInvalidCharacter, got %v", tt.symbol, err)
+			}
+		})
+	}
+}
+
+func TestWithChecksum(t *testing.T) {
+	tests := []struct {
+		name string
+		enc  Encoding
+	}{
+		{"crockford mod 37", WithChecksum(Crockford32, CrockfordMod37)},
+		{"base58 luhn", WithChecksum(Base58Bitcoin, NewLuhn(Base58BitcoinAlphabet))},
+		{"base62 luhn", WithChecksum(Base62, NewLuhn(Base62Alphabet))},
+	}
+
+	rng := rand.New(rand.NewSource(2))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := make([]byte, 16)
+			rng.Read(src)
+
+			s := EncodeToString(tt.enc, src)
+			if len(s) != tt.enc.EncodedLen(16) {
+				t.Fatalf("Encoded length = %d, want %d", len(s), tt.enc.EncodedLen(16))
+			}
+			if n := tt.enc.DecodedLen(len(s)); n != 16 {
+				t.Errorf("DecodedLen(%d) = %d, want 16", len(s), n)
+			}
+
+			dst := make([]byte, 16)
+			if err := tt.enc.Decode(dst, []byte(s)); err != nil {
+				t.Fatalf("Decode(%q) error = %v", s, err)
+			}
+			if !bytes.Equal(dst, src) {
+				t.Errorf("Decode() = %x, want %x", dst, src)
+			}
+
+			// Changing the last payload character breaks the checksum but not the encoding
+			typo := []byte(s)
+			last := len(typo) - 2
+			if typo[last] == '2' {
+				typo[last] = '3'
+			} else {
+				typo[last] = '2'
+			}
+			err := tt.enc.Decode(dst, typo)
+			if !errors.Is(err, ErrChecksumMismatch) || errors.Is(err, ErrMalformed) {
+				t.Errorf("Decode(%q) expected only ErrChecksumMismatch, got %v", typo, err)
+			}
+			if !bytes.Equal(dst, make([]byte, 16)) {
+				t.Errorf("Decode() left %x in dst after a checksum failure", dst)
+			}
+
+			if err := tt.enc.Decode(dst, []byte(s[:len(s)-1])); !errors.Is(err, ErrInvalidLength) {
+				t.Errorf("Decode() without check symbol expected ErrInvalidLength, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWithChecksumCrockfordLenient(t *testing.T) {
+	enc := WithChecksum(Crockford32, CrockfordMod37)
+	src := bytes.Repeat([]byte{0xab}, 16)
+	s := EncodeToString(enc, src)
+
+	lower := bytes.ToLower([]byte(s))
+	dst := make([]byte, 16)
+	if err := enc.Decode(dst, lower); err != nil {
+		t.Fatalf("Decode(%q) error = %v", lower, err)
+	}
+	if !bytes.Equal(dst, src) {
+		t.Errorf("Decode() = %x, want %x", dst, src)
+	}
+}
+
+func TestMalformedErrors(t *testing.T) {
+	for _, err := range []error{ErrInvalidLength, ErrInvalidCharacter, ErrOverflow} {
+		if !errors.Is(err, ErrMalformed) {
+			t.Errorf("%v does not wrap ErrMalformed", err)
+		}
+	}
+	if errors.Is(ErrChecksumMismatch, ErrMalformed) {
+		t.Error("ErrChecksumMismatch must not wrap ErrMalformed")
+	}
+}
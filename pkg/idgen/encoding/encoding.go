@@ -0,0 +1,255 @@
+// Package encoding provides fixed-width text encodings for binary IDs.
+//
+// Every codec treats its input as a big-endian unsigned number and writes it
+// left-padded with the alphabet's zero digit, so the encoded length depends only
+// on the input length: a UUID is always 26 characters in Crockford Base32,
+// 22 in Base58 and Base62, and 22 in Base64URL. Apart from Base64URL, the
+// encodings preserve the byte order of their input, so encoded time-ordered
+// IDs still sort lexicographically.
+//
+// Encode and Decode write into caller-provided buffers and never allocate.
+//
+// Example:
+//
+//	uuid, _ := idgen.NewUUIDv7()
+//	short := uuid.Encode(encoding.Base58Bitcoin) // 22 characters
+//	parsed, err := idgen.ParseUUIDWith(encoding.Base58Bitcoin, short)
+package encoding
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Decoding errors. ErrInvalidLength, ErrInvalidCharacter and ErrOverflow all wrap ErrMalformed,
+// so errors.Is(err, ErrMalformed) tells malformed text apart from ErrChecksumMismatch.
+var (
+	// ErrMalformed is wrapped by every error reporting text that is not a valid encoding
+	ErrMalformed = errors.New("malformed encoded text")
+
+	// ErrInvalidLength is returned when the encoded text or the destination has the wrong length
+	ErrInvalidLength = fmt.Errorf("%w: invalid length", ErrMalformed)
+
+	// ErrInvalidCharacter is returned when the encoded text contains a character outside the alphabet
+	ErrInvalidCharacter = fmt.Errorf("%w: invalid character", ErrMalformed)
+
+	// ErrOverflow is returned when the encoded value does not fit in the destination
+	ErrOverflow = fmt.Errorf("%w: value overflows destination", ErrMalformed)
+)
+
+// Encoding converts fixed-size binary values to text and back
+type Encoding interface {
+	// EncodedLen returns the length in bytes of the encoding of n source bytes
+	EncodedLen(n int) int
+
+	// DecodedLen returns the number of bytes decoded from n encoded bytes
+	DecodedLen(n int) int
+
+	// Encode writes the encoding of src into dst, which must be EncodedLen(len(src)) bytes long
+	Encode(dst, src []byte)
+
+	// Decode decodes src into dst. len(src) must equal EncodedLen(len(dst)).
+	Decode(dst, src []byte) error
+}
+
+// Alphabets of the provided encodings
+const (
+	// Crockford32Alphabet is Douglas Crockford's Base32 alphabet, also used by ULID
+	Crockford32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// Base58BitcoinAlphabet is the Base58 alphabet used by Bitcoin
+	Base58BitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	// Base58FlickrAlphabet is the Base58 alphabet used by Flickr short URLs
+	Base58FlickrAlphabet = "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+	// Base62Alphabet is the Base62 alphabet in ASCII order, also used by KSUID
+	Base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// Provided encodings
+var (
+	// Crockford32 is Crockford Base32. Decoding is case-insensitive and accepts
+	// I and L for 1 and O for 0. A 16-byte value encodes exactly like a ULID.
+	Crockford32 Encoding = newCrockford32()
+
+	// Base58Bitcoin is fixed-width Base58 with the Bitcoin alphabet
+	Base58Bitcoin Encoding = NewRadix(Base58BitcoinAlphabet)
+
+	// Base58Flickr is fixed-width Base58 with the Flickr alphabet
+	Base58Flickr Encoding = NewRadix(Base58FlickrAlphabet)
+
+	// Base62 is fixed-width Base62. A 20-byte value encodes exactly like a KSUID.
+	Base62 Encoding = NewRadix(Base62Alphabet)
+
+	// Base64URL is unpadded Base64 with the URL-safe alphabet (RFC 4648 §5).
+	// Unlike the other encodings it does not preserve sort order.
+	Base64URL Encoding = base64URL{}
+)
+
+// Radix is a fixed-width positional encoding over an arbitrary alphabet
+type Radix struct {
+	alphabet  string
+	base      int
+	bitsPer   float64
+	decodeMap [256]int16
+}
+
+// NewRadix creates a fixed-width encoding that writes values as numbers in the base
+// of the alphabet, most significant digit first. It panics if the alphabet has fewer
+// than 2 or more than 256 characters or repeats a character.
+func NewRadix(alphabet string) *Radix {
+	if len(alphabet) < 2 || len(alphabet) > 256 {
+		panic("encoding: alphabet must have between 2 and 256 characters")
+	}
+
+	r := &Radix{alphabet: alphabet, base: len(alphabet), bitsPer: math.Log2(float64(len(alphabet)))}
+	for i := range r.decodeMap {
+		r.decodeMap[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		if r.decodeMap[alphabet[i]] >= 0 {
+			panic("encoding: alphabet repeats " + string(alphabet[i]))
+		}
+		r.decodeMap[alphabet[i]] = int16(i)
+	}
+	return r
+}
+
+// Alphabet returns the alphabet of the encoding
+func (r *Radix) Alphabet() string {
+	return r.alphabet
+}
+
+// EncodedLen returns the number of digits needed for any value of n bytes
+func (r *Radix) EncodedLen(n int) int {
+	return int(math.Ceil(float64(n*8) / r.bitsPer))
+}
+
+// DecodedLen returns the largest number of bytes that n digits always fit in
+func (r *Radix) DecodedLen(n int) int {
+	return int(float64(n) * r.bitsPer / 8)
+}
+
+// Encode writes src as a fixed-width number into dst.
+// It panics if len(dst) != EncodedLen(len(src)).
+func (r *Radix) Encode(dst, src []byte) {
+	if len(dst) != r.EncodedLen(len(src)) {
+		panic("encoding: destination has the wrong length")
+	}
+
+	// dst holds digit values while the number is built, most significant first
+	clear(dst)
+	for _, b := range src {
+		carry := int(b)
+		for j := len(dst) - 1; j >= 0; j-- {
+			carry += int(dst[j]) << 8
+			dst[j] = byte(carry % r.base)
+			carry /= r.base
+		}
+	}
+	for i, digit := range dst {
+		dst[i] = r.alphabet[digit]
+	}
+}
+
+// Decode parses the fixed-width number in src into dst
+func (r *Radix) Decode(dst, src []byte) error {
+	return decodeRadix(dst, src, r.EncodedLen(len(dst)), r.base, &r.decodeMap)
+}
+
+// decodeRadix converts digits to a big-endian number, using decodeMap to find digit values
+func decodeRadix(dst, src []byte, encodedLen, base int, decodeMap *[256]int16) error {
+	if len(src) != encodedLen {
+		return fmt.Errorf("%w: got %d characters, want %d", ErrInvalidLength, len(src), encodedLen)
+	}
+
+	clear(dst)
+	for i, c := range src {
+		digit := decodeMap[c]
+		if digit < 0 {
+			return fmt.Errorf("%w %q at position %d", ErrInvalidCharacter, c, i)
+		}
+		carry := int(digit)
+		for j := len(dst) - 1; j >= 0; j-- {
+			carry += int(dst[j]) * base
+			dst[j] = byte(carry)
+			carry >>= 8
+		}
+		if carry != 0 {
+			return ErrOverflow
+		}
+	}
+	return nil
+}
+
+// crockford32 is Radix with Crockford's decoding aliases
+type crockford32 struct {
+	*Radix
+	lenient [256]int16
+}
+
+// newCrockford32 creates the Crockford Base32 encoding
+func newCrockford32() *crockford32 {
+	c := &crockford32{Radix: NewRadix(Crockford32Alphabet)}
+	c.lenient = c.decodeMap
+	for i := 0; i < len(Crockford32Alphabet); i++ {
+		upper := Crockford32Alphabet[i]
+		if upper >= 'A' && upper <= 'Z' {
+			c.lenient[upper+'a'-'A'] = int16(i)
+		}
+	}
+	for _, alias := range []struct {
+		c     byte
+		digit int16
+	}{{'O', 0}, {'o', 0}, {'I', 1}, {'i', 1}, {'L', 1}, {'l', 1}} {
+		c.lenient[alias.c] = alias.digit
+	}
+	return c
+}
+
+// Decode parses Crockford Base32 case-insensitively, mapping I and L to 1 and O to 0
+func (c *crockford32) Decode(dst, src []byte) error {
+	return decodeRadix(dst, src, c.EncodedLen(len(dst)), c.base, &c.lenient)
+}
+
+// strictBase64URL rejects non-zero trailing bits, so every value has exactly one encoding
+var strictBase64URL = base64.RawURLEncoding.Strict()
+
+// base64URL adapts base64.RawURLEncoding to Encoding
+type base64URL struct{}
+
+// EncodedLen returns the unpadded Base64 length of n bytes
+func (base64URL) EncodedLen(n int) int {
+	return base64.RawURLEncoding.EncodedLen(n)
+}
+
+// DecodedLen returns the number of bytes in n unpadded Base64 characters
+func (base64URL) DecodedLen(n int) int {
+	return base64.RawURLEncoding.DecodedLen(n)
+}
+
+// Encode writes src as unpadded URL-safe Base64 into dst
+func (base64URL) Encode(dst, src []byte) {
+	base64.RawURLEncoding.Encode(dst, src)
+}
+
+// Decode parses unpadded URL-safe Base64 from src into dst
+func (base64URL) Decode(dst, src []byte) error {
+	if want := base64.RawURLEncoding.EncodedLen(len(dst)); len(src) != want {
+		return fmt.Errorf("%w: got %d characters, want %d", ErrInvalidLength, len(src), want)
+	}
+	if _, err := strictBase64URL.Decode(dst, src); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCharacter, err)
+	}
+	return nil
+}
+
+// EncodeToString returns the encoding of src as a string
+func EncodeToString(enc Encoding, src []byte) string {
+	dst := make([]byte, enc.EncodedLen(len(src)))
+	enc.Encode(dst, src)
+	return string(dst)
+}
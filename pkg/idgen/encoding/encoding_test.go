@@ -0,0 +1,194 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestEncodingVectors(t *testing.T) {
+	ones16 := bytes.Repeat([]byte{0xff}, 16)
+	ones8 := bytes.Repeat([]byte{0xff}, 8)
+	ones20 := bytes.Repeat([]byte{0xff}, 20)
+	value, _ := hex.DecodeString("0123456789abcdef")
+
+	tests := []struct {
+		name string
+		enc  Encoding
+		src  []byte
+		want string
+	}{
+		{"crockford zero", Crockford32, make([]byte, 16), strings.Repeat("0", 26)},
+		{"crockford max", Crockford32, ones16, "7" + strings.Repeat("Z", 25)},
+		{"crockford uint64", Crockford32, ones8, "FZZZZZZZZZZZZ"},
+		{"base58 bitcoin uint64", Base58Bitcoin, ones8, "jpXCZedGfVQ"},
+		{"base58 bitcoin uuid", Base58Bitcoin, ones16, "YcVfxkQb6JRzqk5kF2tNLv"},
+		{"base58 bitcoin value", Base58Bitcoin, value, "1C3CPq7c8PY"},
+		{"base58 flickr value", Base58Flickr, value, "1c3coQ7B8ox"},
+		{"base58 zero", Base58Bitcoin, make([]byte, 8), "11111111111"},
+		{"base62 uint64", Base62, ones8, "LygHa16AHYF"},
+		{"base62 uuid", Base62, ones16, "7n42DGM5Tflk9n8mt7Fhc7"},
+		{"base62 ksuid max", Base62, ones20, "aWgEPTl1tmebfsQzFP4bxwgy80V"},
+		{"base64url", Base64URL, value, "ASNFZ4mrze8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EncodeToString(tt.enc, tt.src); got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+			if n := tt.enc.EncodedLen(len(tt.src)); n != len(tt.want) {
+				t.Errorf("EncodedLen(%d) = %d, want %d", len(tt.src), n, len(tt.want))
+			}
+			if n := tt.enc.DecodedLen(len(tt.want)); n != len(tt.src) {
+				t.Errorf("DecodedLen(%d) = %d, want %d", len(tt.want), n, len(tt.src))
+			}
+
+			dst := make([]byte, len(tt.src))
+			if err := tt.enc.Decode(dst, []byte(tt.want)); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !bytes.Equal(dst, tt.src) {
+				t.Errorf("Decode() = %x, want %x", dst, tt.src)
+			}
+		})
+	}
+}
+
+func TestCrockford32ULID(t *testing.T) {
+	// Sample ULID from the specification decodes and re-encodes unchanged
+	const ulid = "01AN4Z07BY79KA1307SR9X4MV3"
+
+	var b [16]byte
+	if err := Crockford32.Decode(b[:], []byte(ulid)); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got := EncodeToString(Crockford32, b[:]); got != ulid {
+		t.Errorf("Encode() = %q, want %q", got, ulid)
+	}
+
+	// Lowercase and the I/L/O aliases decode to the same value
+	var lenient [16]byte
+	alias := strings.ToLower(strings.ReplaceAll(strings.ReplaceAll(ulid, "0", "O"), "1", "l"))
+	if err := Crockford32.Decode(lenient[:], []byte(alias)); err != nil {
+		t.Fatalf("Decode(%q) error = %v", alias, err)
+	}
+	if lenient != b {
+		t.Errorf("Decode(%q) = %x, want %x", alias, lenient, b)
+	}
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	encodings := map[string]Encoding{
+		"crockford32":    Crockford32,
+		"base58 bitcoin": Base58Bitcoin,
+		"base58 flickr":  Base58Flickr,
+		"base62":         Base62,
+		"base64url":      Base64URL,
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for name, enc := range encodings {
+		t.Run(name, func(t *testing.T) {
+			for _, size := range []int{1, 8, 12, 16, 20, 32} {
+				src := make([]byte, size)
+				dst := make([]byte, size)
+				buf := make([]byte, enc.EncodedLen(size))
+				for i := 0; i < 200; i++ {
+					rng.Read(src)
+					enc.Encode(buf, src)
+					if err := enc.Decode(dst, buf); err != nil {
+						t.Fatalf("Decode(%q) error = %v", buf, err)
+					}
+					if !bytes.Equal(dst, src) {
+						t.Fatalf("Decode(Encode(%x)) = %x", src, dst)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEncodingPreservesOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, enc := range []Encoding{Crockford32, Base58Bitcoin, Base62} {
+		a, b := make([]byte, 16), make([]byte, 16)
+		for i := 0; i < 500; i++ {
+			rng.Read(a)
+			rng.Read(b)
+			ea, eb := EncodeToString(enc, a), EncodeToString(enc, b)
+			if (bytes.Compare(a, b) < 0) != (ea < eb) && !bytes.Equal(a, b) {
+				t.Fatalf("Order of %x and %x not preserved: %q, %q", a, b, ea, eb)
+			}
+		}
+	}
+}
+
+func TestEncodingDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		enc     Encoding
+		dstLen  int
+		src     string
+		wantErr error
+	}{
+		{"short", Base58Bitcoin, 8, "jpXCZedGfV", ErrInvalidLength},
+		{"long", Base62, 8, "LygHa16AHYFA", ErrInvalidLength},
+		{"bad character", Base58Bitcoin, 8, "jpXCZedGf0Q", ErrInvalidCharacter},
+		{"crockford U", Crockford32, 8, "FZZZZZZZZZZZU", ErrInvalidCharacter},
+		{"overflow", Base62, 8, "zzzzzzzzzzz", ErrOverflow},
+		{"crockford overflow", Crockford32, 16, "8" + strings.Repeat("0", 25), ErrOverflow},
+		{"base64 bad character", Base64URL, 8, "ASNFZ4mrz+8", ErrInvalidCharacter},
+		{"base64 trailing bits", Base64URL, 8, "ASNFZ4mrze9", ErrInvalidCharacter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := make([]byte, tt.dstLen)
+			if err := tt.enc.Decode(dst, []byte(tt.src)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode(%q) error = %v, want %v", tt.src, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewRadixPanics(t *testing.T) {
+	for _, alphabet := range []string{"a", "abca"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewRadix(%q) did not panic", alphabet)
+				}
+			}()
+			NewRadix(alphabet)
+		}()
+	}
+}
+
+func TestEncodingAllocations(t *testing.T) {
+	src := bytes.Repeat([]byte{0xab}, 16)
+	dst := make([]byte, 16)
+	for _, enc := range []Encoding{Crockford32, Base58Bitcoin, Base62, Base64URL} {
+		buf := make([]byte, enc.EncodedLen(16))
+		allocs := testing.AllocsPerRun(100, func() {
+			enc.Encode(buf, src)
+			_ = enc.Decode(dst, buf)
+		})
+		if allocs != 0 {
+			t.Errorf("%T allocates %.0f times per Encode/Decode", enc, allocs)
+		}
+	}
+}
+
+func BenchmarkBase58EncodeUUID(b *testing.B) {
+	src := bytes.Repeat([]byte{0xab}, 16)
+	dst := make([]byte, Base58Bitcoin.EncodedLen(16))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Base58Bitcoin.Encode(dst, src)
+	}
+}
@@ -0,0 +1,89 @@
+package encoding
+
+import "strings"
+
+// DefaultSeparator is the group separator used when Formatter.Separator is empty
+const DefaultSeparator = "-"
+
+// Formatter renders encoded IDs for people: split into groups, optionally upper-cased,
+// and parsed back leniently. It works on text, so it applies to any encoding as well as
+// to decimal Snowflake IDs.
+//
+// The separator must not be part of the encoding alphabet (do not use "-" with Base64URL),
+// and Uppercase must only be used with case-insensitive encodings such as Crockford32.
+//
+// Example:
+//
+//	f := encoding.Formatter{GroupSize: 4, Crockford: true}
+//	display := f.Format(idgen.FormatSnowflake(id, encoding.Crockford32)) // 0DQM-8KHZ-9S2V-R
+//	id, err := idgen.ParseSnowflakeWith(encoding.Crockford32, f.Normalize("0dqm 8khz-9s2v-r"))
+type Formatter struct {
+	// GroupSize is the number of characters per group, counted from the left; 0 disables grouping
+	GroupSize int
+
+	// Separator is inserted between groups; DefaultSeparator is used when empty
+	Separator string
+
+	// Uppercase converts ASCII letters to upper case in Format
+	Uppercase bool
+
+	// Crockford makes Normalize apply Crockford Base32 rules: letters are upper-cased,
+	// O becomes 0, and I and L become 1
+	Crockford bool
+}
+
+// separator returns the configured separator or DefaultSeparator
+func (f Formatter) separator() string {
+	if f.Separator == "" {
+		return DefaultSeparator
+	}
+	return f.Separator
+}
+
+// Format returns s split into groups of GroupSize characters
+func (f Formatter) Format(s string) string {
+	sep := f.separator()
+	size := len(s)
+	if f.GroupSize > 0 && len(s) > 0 {
+		size += (len(s) - 1) / f.GroupSize * len(sep)
+	}
+
+	var b strings.Builder
+	b.Grow(size)
+	for i := 0; i < len(s); i++ {
+		if f.GroupSize > 0 && i > 0 && i%f.GroupSize == 0 {
+			b.WriteString(sep)
+		}
+		c := s[i]
+		if f.Uppercase && c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// Normalize reverses Format: it removes separators and spaces, and applies the
+// Crockford rules when enabled. The result can be passed to the encoding's decoder.
+func (f Formatter) Normalize(s string) string {
+	sep := f.separator()
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], sep) {
+			i += len(sep)
+			continue
+		}
+		c := s[i]
+		i++
+		if c == ' ' {
+			continue
+		}
+		if f.Crockford {
+			c = normalizeCrockfordSymbol(c)
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
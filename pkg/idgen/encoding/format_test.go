@@ -0,0 +1,78 @@
+package encoding
+
+import "testing"
+
+func TestFormatterFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Formatter
+		in   string
+		want string
+	}{
+		{"no grouping", Formatter{}, "7xk29qpm", "7xk29qpm"},
+		{"groups of 4", Formatter{GroupSize: 4}, "7XK29QPM3HRT", "7XK2-9QPM-3HRT"},
+		{"uneven last group", Formatter{GroupSize: 4}, "7XK29QPM3H", "7XK2-9QPM-3H"},
+		{"uppercase", Formatter{GroupSize: 4, Uppercase: true}, "7xk29qpm3hrt", "7XK2-9QPM-3HRT"},
+		{"custom separator", Formatter{GroupSize: 3, Separator: " "}, "175928847", "175 928 847"},
+		{"multi-byte separator", Formatter{GroupSize: 2, Separator: " · "}, "abcd", "ab · cd"},
+		{"empty", Formatter{GroupSize: 4}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.Format(tt.in); got != tt.want {
+				t.Errorf("Format(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatterNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Formatter
+		in   string
+		want string
+	}{
+		{"strips separators", Formatter{GroupSize: 4}, "7XK2-9QPM-3HRT", "7XK29QPM3HRT"},
+		{"strips spaces", Formatter{GroupSize: 4}, " 7XK2 9QPM-3HRT ", "7XK29QPM3HRT"},
+		{"keeps case without crockford", Formatter{}, "aBc", "aBc"},
+		{"crockford", Formatter{Crockford: true}, "7xk2-oqpm-il", "7XK20QPM11"},
+		{"custom separator keeps dashes", Formatter{Separator: "."}, "ab.c-d", "abc-d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatterRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		enc  Encoding
+		f    Formatter
+	}{
+		{"crockford", Crockford32, Formatter{GroupSize: 4, Crockford: true}},
+		{"crockford checked", WithChecksum(Crockford32, CrockfordMod37), Formatter{GroupSize: 5, Crockford: true}},
+		{"base58", Base58Bitcoin, Formatter{GroupSize: 4}},
+		{"base62", Base62, Formatter{GroupSize: 6, Separator: " "}},
+	}
+
+	src := []byte{0x01, 0x8f, 0x3a, 0x5c, 0xd2, 0x10, 0x7e, 0x9b, 0x44, 0x0c, 0xaa, 0x55, 0x12, 0x34, 0x56, 0x78}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			display := tt.f.Format(EncodeToString(tt.enc, src))
+			dst := make([]byte, len(src))
+			if err := tt.enc.Decode(dst, []byte(tt.f.Normalize(display))); err != nil {
+				t.Fatalf("Decode(Normalize(%q)) error = %v", display, err)
+			}
+			if string(dst) != string(src) {
+				t.Errorf("Round trip = %x, want %x", dst, src)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrRandomSource is returned when random bytes cannot be read from the entropy source.
+// It wraps the error of the source.
+var ErrRandomSource = errors.New("failed to generate random bytes")
+
+// ErrEntropySourceUnavailable is returned by SetEntropySource when the source
+// is not supported by the Go version the program was built with
+var ErrEntropySourceUnavailable = errors.New("entropy source unavailable")
+
+// EntropySource selects how the shared entropy buffers are refilled
+type EntropySource int32
+
+const (
+	// EntropyCryptoRand refills the buffers from crypto/rand, one system call per buffer
+	EntropyCryptoRand EntropySource = iota
+
+	// EntropyChaCha8 refills the buffers from a ChaCha8 CSPRNG per buffer, seeded from crypto/rand.
+	// It avoids system calls entirely after seeding. It requires Go 1.22 or later.
+	EntropyChaCha8
+)
+
+// entropyBufferSize is the number of random bytes fetched per refill.
+// It is a multiple of 8 so ChaCha8 refills fill it with whole words.
+const entropyBufferSize = 4096
+
+// newChaCha8Refill creates a ChaCha8-backed refill function; it is nil before Go 1.22
+var newChaCha8Refill func() (func([]byte) error, error)
+
+var entropySource atomic.Int32
+
+// SetEntropySource selects the generator behind the shared entropy buffers for the whole process.
+// Buffers filled by the previous source are discarded. The default is EntropyCryptoRand.
+//
+// Returns:
+//   - error: ErrEntropySourceUnavailable if src is not supported by this build
+func SetEntropySource(src EntropySource) error {
+	switch src {
+	case EntropyCryptoRand:
+	case EntropyChaCha8:
+		if newChaCha8Refill == nil {
+			return fmt.Errorf("%w: ChaCha8 requires Go 1.22", ErrEntropySourceUnavailable)
+		}
+	default:
+		return fmt.Errorf("%w: unknown source %d", ErrEntropySourceUnavailable, src)
+	}
+	entropySource.Store(int32(src))
+	return nil
+}
+
+// entropyBuffer holds random bytes that have not been handed out yet.
+// A buffer is owned by one goroutine between entropyPool.Get and entropyPool.Put,
+// so every byte is returned at most once.
+type entropyBuffer struct {
+	source EntropySource
+	refill func([]byte) error
+	off    int
+	buf    [entropyBufferSize]byte
+}
+
+// entropyPool caches one buffer per P, so concurrent generators rarely share a buffer
+var entropyPool sync.Pool
+
+// cryptoRefill fills b from crypto/rand
+func cryptoRefill(b []byte) error {
+	_, err := io.ReadFull(rand.Reader, b)
+	return err
+}
+
+// getEntropyBuffer returns a buffer for the current source
+func getEntropyBuffer() (*entropyBuffer, error) {
+	src := EntropySource(entropySource.Load())
+	if b, ok := entropyPool.Get().(*entropyBuffer); ok && b.source == src {
+		return b, nil
+	}
+
+	b := &entropyBuffer{source: src, refill: cryptoRefill, off: entropyBufferSize}
+	if src == EntropyChaCha8 {
+		refill, err := newChaCha8Refill()
+		if err != nil {
+			return nil, err
+		}
+		b.refill = refill
+	}
+	return b, nil
+}
+
+// readEntropy fills p with cryptographically secure random bytes from the shared buffers.
+// p does not escape, so callers can read into stack arrays without allocating.
+func readEntropy(p []byte) error {
+	b, err := getEntropyBuffer()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRandomSource, err)
+	}
+	defer entropyPool.Put(b)
+
+	for len(p) > 0 {
+		if b.off == len(b.buf) {
+			if err := b.refill(b.buf[:]); err != nil {
+				return fmt.Errorf("%w: %w", ErrRandomSource, err)
+			}
+			b.off = 0
+		}
+		n := copy(p, b.buf[b.off:])
+		// Wipe the bytes handed out, so they do not linger in memory
+		clear(b.buf[b.off : b.off+n])
+		b.off += n
+		p = p[n:]
+	}
+	return nil
+}
+
+// entropyReader reads from the shared entropy buffers
+type entropyReader struct{}
+
+// Read fills p with random bytes; it always fills p completely or returns an error
+func (entropyReader) Read(p []byte) (int, error) {
+	if err := readEntropy(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// EntropyReader returns the shared, goroutine-safe buffered CSPRNG used by the random-based generators.
+// It is refilled in large chunks from the source selected with SetEntropySource, and buffers
+// are cached per P so concurrent readers rarely contend.
+//
+// Example:
+//
+//	var token [32]byte
+//	_, err := io.ReadFull(idgen.EntropyReader(), token[:])
+func EntropyReader() io.Reader {
+	return entropyReader{}
+}
+
+// readerBox wraps an io.Reader so it can be stored in an atomic.Pointer
+type readerBox struct {
+	r io.Reader
+}
+
+// randomSource is embedded in random-based generators to hold their entropy reader.
+// The zero value reads from the shared entropy buffers.
+type randomSource struct {
+	reader atomic.Pointer[readerBox]
+}
+
+// setEntropy replaces the entropy reader; nil restores the shared entropy buffers
+func (s *randomSource) setEntropy(r io.Reader) {
+	if r == nil {
+		s.reader.Store(nil)
+		return
+	}
+	s.reader.Store(&readerBox{r: r})
+}
+
+// read fills p with random bytes from the configured reader.
+// On error the contents of p are unspecified, so callers must discard it.
+func (s *randomSource) read(p []byte) error {
+	if box := s.reader.Load(); box != nil {
+		return readFrom(box.r, p)
+	}
+	return readEntropy(p)
+}
+
+// readFrom fills p from r through a temporary buffer, so p does not escape
+// to the reader and is only written once every byte was read
+func readFrom(r io.Reader, p []byte) error {
+	buf := make([]byte, len(p))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return fmt.Errorf("%w: %w", ErrRandomSource, err)
+	}
+	copy(p, buf)
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build go1.22
+
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	mathrand "math/rand/v2"
+)
+
+func init() {
+	newChaCha8Refill = chaCha8Refill
+}
+
+// chaCha8Refill seeds a ChaCha8 generator from crypto/rand and returns a function
+// that fills buffers from it
+func chaCha8Refill() (func([]byte) error, error) {
+	var seed [32]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return nil, err
+	}
+	c := mathrand.NewChaCha8(seed)
+	clear(seed[:])
+
+	return func(b []byte) error {
+		for i := 0; i+8 <= len(b); i += 8 {
+			binary.LittleEndian.PutUint64(b[i:], c.Uint64())
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,191 @@
+package idgen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEntropyReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		source EntropySource
+	}{
+		{"crypto/rand", EntropyCryptoRand},
+		{"chacha8", EntropyChaCha8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetEntropySource(tt.source); errors.Is(err, ErrEntropySourceUnavailable) {
+				t.Skip(err)
+			} else if err != nil {
+				t.Fatalf("SetEntropySource() error = %v", err)
+			}
+			defer SetEntropySource(EntropyCryptoRand)
+
+			// Reads larger than a buffer span several refills
+			for _, size := range []int{1, 16, entropyBufferSize - 3, 3 * entropyBufferSize} {
+				a := make([]byte, size)
+				b := make([]byte, size)
+				if _, err := io.ReadFull(EntropyReader(), a); err != nil {
+					t.Fatalf("Read(%d) error = %v", size, err)
+				}
+				if _, err := io.ReadFull(EntropyReader(), b); err != nil {
+					t.Fatalf("Read(%d) error = %v", size, err)
+				}
+				if size >= 16 && bytes.Equal(a, b) {
+					t.Errorf("Two reads of %d bytes returned the same bytes", size)
+				}
+			}
+		})
+	}
+}
+
+func TestSetEntropySourceUnknown(t *testing.T) {
+	if err := SetEntropySource(EntropySource(99)); !errors.Is(err, ErrEntropySourceUnavailable) {
+		t.Errorf("Expected ErrEntropySourceUnavailable, got %v", err)
+	}
+}
+
+func TestEntropyConcurrentUniqueness(t *testing.T) {
+	const goroutines = 16
+	const perGoroutine = 2000
+
+	var wg sync.WaitGroup
+	results := make([][]UUID, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			uuids := make([]UUID, perGoroutine)
+			for j := range uuids {
+				uuids[j], _ = NewUUIDv4()
+			}
+			results[i] = uuids
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[UUID]bool, goroutines*perGoroutine)
+	for _, uuids := range results {
+		for _, u := range uuids {
+			if seen[u] {
+				t.Fatalf("Duplicate UUID: %s", u)
+			}
+			seen[u] = true
+		}
+	}
+}
+
+func TestUUIDAllocations(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func() (UUID, error)
+	}{
+		{"NewUUIDv4", NewUUIDv4},
+		{"NewUUIDv7", NewUUIDv7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(100, func() {
+				if _, err := tt.fn(); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs != 0 {
+				t.Errorf("%s allocated %.0f times, want 0", tt.name, allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkEntropy(b *testing.B) {
+	b.Run("crypto/rand", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf [16]byte
+		for i := 0; i < b.N; i++ {
+			if _, err := rand.Read(buf[:]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	sources := []struct {
+		name   string
+		source EntropySource
+	}{
+		{"buffered", EntropyCryptoRand},
+		{"buffered-chacha8", EntropyChaCha8},
+	}
+	for _, s := range sources {
+		b.Run(s.name, func(b *testing.B) {
+			if err := SetEntropySource(s.source); err != nil {
+				b.Skip(err)
+			}
+			defer SetEntropySource(EntropyCryptoRand)
+
+			b.ReportAllocs()
+			var buf [16]byte
+			for i := 0; i < b.N; i++ {
+				if err := readEntropy(buf[:]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(s.name+"/parallel", func(b *testing.B) {
+			if err := SetEntropySource(s.source); err != nil {
+				b.Skip(err)
+			}
+			defer SetEntropySource(EntropyCryptoRand)
+
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				var buf [16]byte
+				for pb.Next() {
+					if err := readEntropy(buf[:]); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestUUIDv7EntropyFailureKeepsState(t *testing.T) {
+	injected := errors.New("injected")
+	g := &UUIDv7Generator{}
+
+	first, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	lastTimestamp, sequence := g.lastTimestamp, g.sequence
+
+	g.SetEntropy(io.MultiReader(bytes.NewReader(make([]byte, 5)), iotest.ErrReader(injected)))
+	uuid, err := g.Generate()
+	if !errors.Is(err, ErrRandomSource) || !errors.Is(err, injected) {
+		t.Fatalf("Generate() error = %v, want ErrRandomSource wrapping the reader error", err)
+	}
+	if uuid != (UUID{}) {
+		t.Errorf("Generate() returned partial UUID %s", uuid)
+	}
+	if g.lastTimestamp != lastTimestamp || g.sequence != sequence {
+		t.Errorf("Failed Generate() advanced the state to %d/%d", g.lastTimestamp, g.sequence)
+	}
+
+	g.SetEntropy(nil)
+	next, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate() after restore: error = %v", err)
+	}
+	if bytes.Compare(next[:8], first[:8]) <= 0 {
+		t.Errorf("UUIDs not increasing after a failure: %s <= %s", next, first)
+	}
+}
@@ -28,3 +28,47 @@ var (
 	// ErrSonyflakeNotImplemented is returned when Sonyflake generation is called
 	ErrSonyflakeNotImplemented = errors.New("sonyflake generation not implemented yet")
 )
+
+// WaitCanceledError is returned by the GenerateContext methods when the context
+// is canceled, or its deadline would expire, while a generator waits for the clock.
+// It wraps the context error, so errors.Is(err, context.DeadlineExceeded) and
+// errors.Is(err, context.Canceled) work as expected.
+type WaitCanceledError struct {
+	// Reason describes why the generator had to wait,
+	// e.g. "sequence exhausted" or "clock moved backwards"
+	Reason string
+
+	// Err is the context error
+	Err error
+}
+
+// Error implements the error interface
+func (e *WaitCanceledError) Error() string {
+	return "idgen: wait canceled (" + e.Reason + "): " + e.Err.Error()
+}
+
+// Unwrap returns the underlying context error
+func (e *WaitCanceledError) Unwrap() error {
+	return e.Err
+}
+
+// OptionError is returned by NewSnowflakeGenerator when an option is invalid,
+// alone or in combination with the others. It wraps the cause, so
+// errors.Is(err, ErrInvalidWorkerID) and similar checks work as expected.
+type OptionError struct {
+	// Option is the name of the offending option, e.g. "WithNode"
+	Option string
+
+	// Err is the cause, e.g. ErrInvalidLayout or ErrInvalidProcessID
+	Err error
+}
+
+// Error implements the error interface
+func (e *OptionError) Error() string {
+	return "idgen: invalid option " + e.Option + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
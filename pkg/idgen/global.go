@@ -1,13 +1,46 @@
 package idgen
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
 	"sync"
 )
 
+// ErrNotInitialized is returned when the global Snowflake generator is used before
+// SetDefaultMachineID or MustInit, and no lazy initializer could configure it
+var ErrNotInitialized = errors.New("default generator not initialized")
+
+// ErrInvalidNodeID is returned when a combined node ID, which covers the process
+// and worker IDs together, is out of range
+var ErrInvalidNodeID = errors.New("invalid node ID")
+
+// Environment variables read by SnowflakeFromEnv
+const (
+	// EnvProcessID holds the process ID (0-31) of the global generator
+	EnvProcessID = "IDGEN_PROCESS_ID"
+
+	// EnvWorkerID holds the worker ID (0-31) of the global generator
+	EnvWorkerID = "IDGEN_WORKER_ID"
+
+	// EnvNodeID holds a combined node ID (0-1023), split into processID = node >> 5
+	// and workerID = node & 31. It is used when EnvProcessID and EnvWorkerID are not set,
+	// e.g. with a StatefulSet ordinal.
+	EnvNodeID = "IDGEN_NODE_ID"
+)
+
 var (
 	// defaultGenerator is the global Snowflake generator
 	defaultGenerator *Snowflake
 	defaultMu        sync.RWMutex
+
+	// lazyInit configures defaultGenerator on first use when it is nil
+	lazyInit func() (*Snowflake, error)
+
+	// lazyInitMu serializes runs of lazyInit without holding defaultMu
+	lazyInitMu sync.Mutex
 )
 
 // SetDefaultMachineID configures the global Snowflake generator with processID and workerID
@@ -34,31 +67,207 @@ func SetDefaultGenerator(generator *Snowflake) {
 	defaultMu.Unlock()
 }
 
-// GenerateSnowflake generates a Snowflake ID using the global generator
-// Panics if SetDefaultMachineID was not called first
-func GenerateSnowflake() int64 {
+// MustInit configures the global Snowflake generator like SetDefaultMachineID,
+// but panics if the IDs are out of range. Call it once at application startup.
+//
+// Example:
+//
+//	func main() {
+//	    idgen.MustInit(1, 3)
+//	    ...
+//	}
+func MustInit(processID, workerID int64) {
+	if err := SetDefaultMachineID(processID, workerID); err != nil {
+		panic("idgen: " + err.Error())
+	}
+}
+
+// SetLazyInit registers a function that configures the global Snowflake generator
+// on first use, when SetDefaultMachineID was never called. Passing nil removes it.
+// The function runs at most once successfully; after a failure it is retried on the next call.
+//
+// The function runs without holding the global generator lock, so it may call
+// SetDefaultMachineID or GetDefaultGenerator. It must not generate IDs with the
+// global generator (GenerateSnowflake and friends), which would wait for itself.
+//
+// Example:
+//
+//	idgen.SetLazyInit(idgen.SnowflakeFromEnv)
+//	id, err := idgen.GenerateSnowflakeE() // configured from IDGEN_PROCESS_ID and IDGEN_WORKER_ID
+func SetLazyInit(init func() (*Snowflake, error)) {
+	defaultMu.Lock()
+	lazyInit = init
+	defaultMu.Unlock()
+}
+
+// SnowflakeFromEnv creates a Snowflake generator from the environment: EnvProcessID
+// and EnvWorkerID when both are set, otherwise EnvNodeID. It is meant for SetLazyInit.
+// Setting only one of EnvProcessID and EnvWorkerID is an error, even when EnvNodeID is set.
+//
+// Returns:
+//   - *Snowflake: A new ID generator instance
+//   - error: ErrNotInitialized if no variable is set, ErrInvalidProcessID or
+//     ErrInvalidWorkerID if a value is missing or not a valid ID, or ErrInvalidNodeID
+//     if EnvNodeID is not a valid node ID
+func SnowflakeFromEnv() (*Snowflake, error) {
+	processID, workerID, err := nodeFromEnv(DefaultLayout)
+	if err != nil {
+		return nil, err
+	}
+	return NewSnowflake(processID, workerID)
+}
+
+// nodeFromEnv reads the process and worker IDs from the environment like SnowflakeFromEnv,
+// checking them against layout and splitting EnvNodeID at its worker ID bits
+func nodeFromEnv(layout Layout) (processID, workerID int64, err error) {
+	process, hasProcess := os.LookupEnv(EnvProcessID)
+	worker, hasWorker := os.LookupEnv(EnvWorkerID)
+	switch {
+	case hasProcess && !hasWorker:
+		return 0, 0, fmt.Errorf("%w: %s is set but %s is not", ErrInvalidWorkerID, EnvProcessID, EnvWorkerID)
+	case hasWorker && !hasProcess:
+		return 0, 0, fmt.Errorf("%w: %s is set but %s is not", ErrInvalidProcessID, EnvWorkerID, EnvProcessID)
+	case hasProcess && hasWorker:
+		processID, err := strconv.ParseInt(process, 10, 64)
+		if err != nil || processID < 0 || processID > layout.MaxProcessID() {
+			return 0, 0, fmt.Errorf("%w: %s=%q must be between 0 and %d", ErrInvalidProcessID, EnvProcessID, process, layout.MaxProcessID())
+		}
+		workerID, err := strconv.ParseInt(worker, 10, 64)
+		if err != nil || workerID < 0 || workerID > layout.MaxWorkerID() {
+			return 0, 0, fmt.Errorf("%w: %s=%q must be between 0 and %d", ErrInvalidWorkerID, EnvWorkerID, worker, layout.MaxWorkerID())
+		}
+		return processID, workerID, nil
+	}
+
+	if node, ok := os.LookupEnv(EnvNodeID); ok {
+		maxNode := (layout.MaxProcessID()+1)<<layout.WorkerIDBits - 1
+		nodeID, err := strconv.ParseInt(node, 10, 64)
+		if err != nil || nodeID < 0 || nodeID > maxNode {
+			return 0, 0, fmt.Errorf("%w: %s=%q must be between 0 and %d", ErrInvalidNodeID, EnvNodeID, node, maxNode)
+		}
+		return nodeID >> layout.WorkerIDBits, nodeID & layout.MaxWorkerID(), nil
+	}
+
+	return 0, 0, fmt.Errorf("%w: set %s and %s, or %s", ErrNotInitialized, EnvProcessID, EnvWorkerID, EnvNodeID)
+}
+
+// loadDefaultGenerator returns the global generator, running the lazy initializer if needed.
+// The initializer runs under lazyInitMu only, so it may use SetDefaultMachineID and
+// GetDefaultGenerator; its result is published under defaultMu.
+func loadDefaultGenerator() (*Snowflake, error) {
 	defaultMu.RLock()
 	gen := defaultGenerator
 	defaultMu.RUnlock()
+	if gen != nil {
+		return gen, nil
+	}
+
+	lazyInitMu.Lock()
+	defer lazyInitMu.Unlock()
+
+	defaultMu.RLock()
+	gen, init := defaultGenerator, lazyInit
+	defaultMu.RUnlock()
+	if gen != nil {
+		return gen, nil
+	}
+	if init == nil {
+		return nil, ErrNotInitialized
+	}
+
+	gen, err := init()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotInitialized, err)
+	}
 
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultGenerator != nil {
+		// The initializer, or another goroutine, configured the generator explicitly
+		return defaultGenerator, nil
+	}
 	if gen == nil {
-		panic("idgen: default generator not initialized. Call SetDefaultMachineID(processID, workerID) first")
+		return nil, ErrNotInitialized
 	}
+	defaultGenerator = gen
+	return gen, nil
+}
+
+// mustLoadDefaultGenerator returns the global generator or panics
+func mustLoadDefaultGenerator() *Snowflake {
+	gen, err := loadDefaultGenerator()
+	if err != nil {
+		panic("idgen: " + err.Error() + ". Call SetDefaultMachineID(processID, workerID) first")
+	}
+	return gen
+}
 
-	return gen.Generate()
+// GenerateSnowflake generates a Snowflake ID using the global generator
+// Panics if SetDefaultMachineID was not called first and no lazy initializer succeeded.
+// Use GenerateSnowflakeE to get an error instead.
+func GenerateSnowflake() int64 {
+	if o := snowflakeOverrideFn.Load(); o != nil {
+		return o.fn()
+	}
+	return mustLoadDefaultGenerator().Generate()
+}
+
+// GenerateSnowflakeE generates a Snowflake ID using the global generator, without panicking.
+//
+// Returns:
+//   - int64: A unique 64-bit Snowflake ID
+//   - error: ErrNotInitialized if the global generator is not configured, ErrEpochExhausted,
+//     or ErrStateStore if the generator has a failing state store
+//
+// Example:
+//
+//	id, err := idgen.GenerateSnowflakeE()
+//	if err != nil {
+//	    return fmt.Errorf("create job: %w", err)
+//	}
+func GenerateSnowflakeE() (int64, error) {
+	if o := snowflakeOverrideFn.Load(); o != nil {
+		return o.fn(), nil
+	}
+
+	gen, err := loadDefaultGenerator()
+	if err != nil {
+		return 0, err
+	}
+	return gen.GenerateContext(context.Background())
 }
 
 // GenerateSnowflakeBatch generates multiple Snowflake IDs using the global generator
 func GenerateSnowflakeBatch(count int) []int64 {
-	defaultMu.RLock()
-	gen := defaultGenerator
-	defaultMu.RUnlock()
+	if o := snowflakeOverrideFn.Load(); o != nil {
+		ids := make([]int64, count)
+		for i := range ids {
+			ids[i] = o.fn()
+		}
+		return ids
+	}
+	return mustLoadDefaultGenerator().GenerateBatch(count)
+}
 
-	if gen == nil {
-		panic("idgen: default generator not initialized. Call SetDefaultMachineID(processID, workerID) first")
+// GenerateSnowflakeBatchE generates multiple Snowflake IDs using the global generator, without panicking.
+//
+// Returns:
+//   - []int64: Slice of unique Snowflake IDs, or nil on error
+//   - error: ErrNotInitialized if the global generator is not configured, ErrEpochExhausted,
+//     or ErrStateStore if the generator has a failing state store
+func GenerateSnowflakeBatchE(count int) ([]int64, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %d", count)
+	}
+	if o := snowflakeOverrideFn.Load(); o != nil {
+		return GenerateSnowflakeBatch(count), nil
 	}
 
-	return gen.GenerateBatch(count)
+	gen, err := loadDefaultGenerator()
+	if err != nil {
+		return nil, err
+	}
+	return gen.GenerateBatchE(count)
 }
 
 // GetDefaultGenerator returns the global Snowflake generator
@@ -1,7 +1,10 @@
 package idgen
 
 import (
+	"errors"
+	"os"
 	"testing"
+	"time"
 )
 
 func TestSetDefaultMachineID(t *testing.T) {
@@ -148,6 +151,136 @@ func TestGetDefaultGenerator(t *testing.T) {
 	}
 }
 
+// resetDefaultGenerator clears the global generator and lazy initializer for the rest of the test
+func resetDefaultGenerator(t *testing.T) {
+	SetDefaultGenerator(nil)
+	SetLazyInit(nil)
+	t.Cleanup(func() {
+		SetDefaultGenerator(nil)
+		SetLazyInit(nil)
+	})
+}
+
+func TestGenerateSnowflakeE(t *testing.T) {
+	resetDefaultGenerator(t)
+
+	if _, err := GenerateSnowflakeE(); !errors.Is(err, ErrNotInitialized) {
+		t.Fatalf("Expected ErrNotInitialized, got %v", err)
+	}
+	if ids, err := GenerateSnowflakeBatchE(3); !errors.Is(err, ErrNotInitialized) || ids != nil {
+		t.Fatalf("Expected nil and ErrNotInitialized, got %v, %v", ids, err)
+	}
+
+	MustInit(6, 9)
+	id, err := GenerateSnowflakeE()
+	if err != nil {
+		t.Fatalf("GenerateSnowflakeE() error = %v", err)
+	}
+	if parts := SnowflakeID(id).Components(); parts.ProcessID != 6 || parts.WorkerID != 9 {
+		t.Errorf("Expected process 6 and worker 9, got %+v", parts)
+	}
+
+	ids, err := GenerateSnowflakeBatchE(100)
+	if err != nil || len(ids) != 100 {
+		t.Fatalf("GenerateSnowflakeBatchE() = %d IDs, %v", len(ids), err)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("Batch not strictly increasing at index %d", i)
+		}
+	}
+	if _, err := GenerateSnowflakeBatchE(-1); err == nil {
+		t.Error("Expected error for negative count")
+	}
+}
+
+func TestMustInitInvalid(t *testing.T) {
+	resetDefaultGenerator(t)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for invalid IDs")
+		}
+	}()
+	MustInit(32, 0)
+}
+
+func TestSnowflakeFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		processID int64
+		workerID  int64
+		expectErr error
+	}{
+		{"process and worker", map[string]string{EnvProcessID: "3", EnvWorkerID: "17"}, 3, 17, nil},
+		{"node ID", map[string]string{EnvNodeID: "105"}, 3, 9, nil},
+		{"process and worker take precedence", map[string]string{EnvProcessID: "1", EnvWorkerID: "2", EnvNodeID: "1023"}, 1, 2, nil},
+		{"nothing set", map[string]string{}, 0, 0, ErrNotInitialized},
+		{"invalid process", map[string]string{EnvProcessID: "x", EnvWorkerID: "1"}, 0, 0, ErrInvalidProcessID},
+		{"worker out of range", map[string]string{EnvProcessID: "1", EnvWorkerID: "32"}, 0, 0, ErrInvalidWorkerID},
+		{"node out of range", map[string]string{EnvNodeID: "1024"}, 0, 0, ErrInvalidNodeID},
+		{"node not a number", map[string]string{EnvNodeID: "node-3"}, 0, 0, ErrInvalidNodeID},
+		{"process without worker", map[string]string{EnvProcessID: "1"}, 0, 0, ErrInvalidWorkerID},
+		{"worker without process", map[string]string{EnvWorkerID: "1", EnvNodeID: "5"}, 0, 0, ErrInvalidProcessID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{EnvProcessID, EnvWorkerID, EnvNodeID} {
+				t.Setenv(key, "")
+				unsetEnv(t, key)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			gen, err := SnowflakeFromEnv()
+			if !errors.Is(err, tt.expectErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if gen.ProcessID() != tt.processID || gen.WorkerID() != tt.workerID {
+				t.Errorf("Expected %d/%d, got %d/%d", tt.processID, tt.workerID, gen.ProcessID(), gen.WorkerID())
+			}
+		})
+	}
+}
+
+func TestSetLazyInit(t *testing.T) {
+	resetDefaultGenerator(t)
+
+	calls := 0
+	fail := errors.New("not ready")
+	SetLazyInit(func() (*Snowflake, error) {
+		calls++
+		if calls == 1 {
+			return nil, fail
+		}
+		return New(4, 4)
+	})
+
+	if _, err := GenerateSnowflakeE(); !errors.Is(err, ErrNotInitialized) || !errors.Is(err, fail) {
+		t.Fatalf("Expected ErrNotInitialized wrapping the initializer error, got %v", err)
+	}
+
+	// A failed initialization is retried, and a successful one runs only once
+	for i := 0; i < 3; i++ {
+		if _, err := GenerateSnowflakeE(); err != nil {
+			t.Fatalf("GenerateSnowflakeE() error = %v", err)
+		}
+	}
+	GenerateSnowflake()
+	if calls != 2 {
+		t.Errorf("Expected the initializer to run twice, ran %d times", calls)
+	}
+	if gen := GetDefaultGenerator(); gen == nil || gen.ProcessID() != 4 {
+		t.Errorf("Expected the lazy generator to become the default, got %v", gen)
+	}
+}
+
 // Benchmarks
 func BenchmarkGenerateSnowflakeGlobal(b *testing.B) {
 	_ = SetDefaultMachineID(1, 1)
@@ -166,3 +299,43 @@ func BenchmarkGenerateSnowflakeGlobalParallel(b *testing.B) {
 		}
 	})
 }
+
+// unsetEnv removes key from the environment; t.Setenv restores it when the test ends
+func TestSetLazyInitCallsGlobalAPI(t *testing.T) {
+	resetDefaultGenerator(t)
+
+	SetLazyInit(func() (*Snowflake, error) {
+		if GetDefaultGenerator() != nil {
+			return nil, errors.New("generator already configured")
+		}
+		if err := SetDefaultMachineID(7, 8); err != nil {
+			return nil, err
+		}
+		return GetDefaultGenerator(), nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := GenerateSnowflakeE()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("GenerateSnowflakeE() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GenerateSnowflakeE() deadlocked in the lazy initializer")
+	}
+	if g := GetDefaultGenerator(); g == nil || g.ProcessID() != 7 || g.WorkerID() != 8 {
+		t.Errorf("GetDefaultGenerator() = %v, want process 7 and worker 8", g)
+	}
+}
+
+func unsetEnv(t *testing.T, key string) {
+	t.Helper()
+	if err := os.Unsetenv(key); err != nil {
+		t.Fatalf("Unsetenv(%s) error = %v", key, err)
+	}
+}
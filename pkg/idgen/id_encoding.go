@@ -0,0 +1,128 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen/encoding"
+)
+
+// ErrInvalidUUID is returned when a UUID cannot be parsed
+var ErrInvalidUUID = errors.New("invalid UUID")
+
+// Encode returns the UUID in an alternative text encoding.
+//
+// Parameters:
+//   - enc: The encoding, e.g. encoding.Base58Bitcoin or encoding.Crockford32
+//
+// Returns:
+//   - string: The encoded UUID; its length depends only on enc
+//
+// Example:
+//
+//	uuid, _ := idgen.NewUUIDv7()
+//	short := uuid.Encode(encoding.Base62) // 22 characters instead of 36
+func (u UUID) Encode(enc encoding.Encoding) string {
+	return encoding.EncodeToString(enc, u[:])
+}
+
+// ParseUUIDWith parses a UUID produced by UUID.Encode with the same encoding.
+//
+// Returns:
+//   - UUID: The parsed UUID
+//   - error: ErrInvalidUUID if s is not a valid encoding of 16 bytes
+func ParseUUIDWith(enc encoding.Encoding, s string) (UUID, error) {
+	var uuid UUID
+	if err := enc.Decode(uuid[:], []byte(s)); err != nil {
+		return UUID{}, fmt.Errorf("%w: %w", ErrInvalidUUID, err)
+	}
+	return uuid, nil
+}
+
+// FormatSnowflake returns a Snowflake ID in an alternative text encoding.
+// The ID is encoded as 8 big-endian bytes, so encodings that preserve order
+// also preserve the order of the IDs.
+//
+// Parameters:
+//   - id: A Snowflake ID
+//   - enc: The encoding, e.g. encoding.Base58Bitcoin or encoding.Crockford32
+//
+// Returns:
+//   - string: The encoded ID; its length depends only on enc
+//
+// Example:
+//
+//	short := idgen.FormatSnowflake(generator.Generate(), encoding.Base58Bitcoin) // 11 characters
+func FormatSnowflake(id int64, enc encoding.Encoding) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(id))
+	return encoding.EncodeToString(enc, b[:])
+}
+
+// ParseSnowflakeWith parses a Snowflake ID produced by FormatSnowflake with the same encoding.
+//
+// Returns:
+//   - int64: The parsed ID
+//   - error: ErrInvalidSnowflakeID if s is not a valid encoding of a non-negative ID
+func ParseSnowflakeWith(enc encoding.Encoding, s string) (int64, error) {
+	var b [8]byte
+	if err := enc.Decode(b[:], []byte(s)); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, err)
+	}
+	id := int64(binary.BigEndian.Uint64(b[:]))
+	if id < 0 {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, ErrSnowflakeNegative)
+	}
+	return id, nil
+}
+
+// FormatSnowflakeWithCheck returns the decimal form of a Snowflake ID followed by a check digit,
+// so IDs read aloud or typed by hand can be verified before a lookup.
+//
+// Parameters:
+//   - id: A non-negative Snowflake ID
+//   - cs: A checksum over decimal digits, e.g. encoding.Damm or encoding.LuhnDecimal
+//
+// Returns:
+//   - string: The decimal ID with one extra trailing digit
+//   - error: ErrInvalidSnowflakeID wrapping ErrSnowflakeNegative for a negative ID,
+//     or wrapping encoding.ErrInvalidCharacter if cs does not cover decimal digits
+//
+// Example:
+//
+//	s, err := idgen.FormatSnowflakeWithCheck(generator.Generate(), encoding.Damm)
+func FormatSnowflakeWithCheck(id int64, cs encoding.Checksum) (string, error) {
+	if id < 0 {
+		return "", fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, ErrSnowflakeNegative)
+	}
+	buf := strconv.AppendInt(make([]byte, 0, 21), id, 10)
+	symbol, err := cs.CheckSymbol(buf)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, err)
+	}
+	return string(append(buf, symbol)), nil
+}
+
+// ParseSnowflakeWithCheck parses a Snowflake ID produced by FormatSnowflakeWithCheck with the same checksum.
+//
+// Returns:
+//   - int64: The parsed ID
+//   - error: ErrInvalidSnowflakeID wrapping encoding.ErrChecksumMismatch if the check digit is wrong,
+//     or wrapping encoding.ErrMalformed if s is not a decimal ID followed by a check digit
+func ParseSnowflakeWithCheck(cs encoding.Checksum, s string) (int64, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, encoding.ErrInvalidLength)
+	}
+
+	payload := s[:len(s)-1]
+	id, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil || id < 0 || payload[0] == '+' {
+		return 0, fmt.Errorf("%w: %w: %q", ErrInvalidSnowflakeID, encoding.ErrInvalidCharacter, s)
+	}
+	if err := cs.Verify([]byte(payload), s[len(s)-1]); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,133 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen/encoding"
+)
+
+func TestUUIDEncode(t *testing.T) {
+	uuid, _ := NewUUIDv7()
+
+	tests := []struct {
+		name   string
+		enc    encoding.Encoding
+		length int
+	}{
+		{"crockford32", encoding.Crockford32, 26},
+		{"base58", encoding.Base58Bitcoin, 22},
+		{"base62", encoding.Base62, 22},
+		{"base64url", encoding.Base64URL, 22},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := uuid.Encode(tt.enc)
+			if len(s) != tt.length {
+				t.Errorf("Encode() = %q, want %d characters", s, tt.length)
+			}
+			parsed, err := ParseUUIDWith(tt.enc, s)
+			if err != nil {
+				t.Fatalf("ParseUUIDWith() error = %v", err)
+			}
+			if parsed != uuid {
+				t.Errorf("ParseUUIDWith() = %s, want %s", parsed, uuid)
+			}
+		})
+	}
+
+	if _, err := ParseUUIDWith(encoding.Base58Bitcoin, "short"); !errors.Is(err, ErrInvalidUUID) || !errors.Is(err, encoding.ErrInvalidLength) {
+		t.Errorf("ParseUUIDWith() error = %v, want ErrInvalidUUID wrapping ErrInvalidLength", err)
+	}
+}
+
+func TestFormatSnowflake(t *testing.T) {
+	generator, _ := New(1, 1)
+	ids := generator.GenerateBatch(100)
+
+	for _, enc := range []encoding.Encoding{encoding.Crockford32, encoding.Base58Bitcoin, encoding.Base62, encoding.Base64URL} {
+		var prev string
+		for i, id := range ids {
+			s := FormatSnowflake(id, enc)
+			parsed, err := ParseSnowflakeWith(enc, s)
+			if err != nil || parsed != id {
+				t.Fatalf("ParseSnowflakeWith(%q) = %d, %v, want %d", s, parsed, err, id)
+			}
+			if enc != encoding.Base64URL && i > 0 && s <= prev {
+				t.Fatalf("%T does not preserve order: %q <= %q", enc, s, prev)
+			}
+			prev = s
+		}
+	}
+
+	// Negative values decode but are not valid Snowflake IDs
+	negative := FormatSnowflake(-1, encoding.Base62)
+	if _, err := ParseSnowflakeWith(encoding.Base62, negative); !errors.Is(err, ErrInvalidSnowflakeID) {
+		t.Errorf("ParseSnowflakeWith(negative) error = %v, want ErrInvalidSnowflakeID", err)
+	}
+	if _, err := ParseSnowflakeWith(encoding.Base62, "!!!!!!!!!!!"); !errors.Is(err, encoding.ErrInvalidCharacter) {
+		t.Errorf("ParseSnowflakeWith() error = %v, want ErrInvalidCharacter", err)
+	}
+}
+
+func TestSnowflakeWithCheck(t *testing.T) {
+	for _, cs := range []encoding.Checksum{encoding.Damm, encoding.LuhnDecimal} {
+		s, err := FormatSnowflakeWithCheck(175928847299117063, cs)
+		if err != nil || len(s) != 19 || s[:18] != "175928847299117063" {
+			t.Fatalf("FormatSnowflakeWithCheck() = %q, %v", s, err)
+		}
+
+		id, err := ParseSnowflakeWithCheck(cs, s)
+		if err != nil || id != 175928847299117063 {
+			t.Fatalf("ParseSnowflakeWithCheck(%q) = %d, %v", s, id, err)
+		}
+
+		typo := []byte(s)
+		typo[3], typo[4] = typo[4], typo[3]
+		_, err = ParseSnowflakeWithCheck(cs, string(typo))
+		if !errors.Is(err, ErrInvalidSnowflakeID) || !errors.Is(err, encoding.ErrChecksumMismatch) {
+			t.Errorf("ParseSnowflakeWithCheck(%q) expected checksum mismatch, got %v", typo, err)
+		}
+	}
+
+	if s, err := FormatSnowflakeWithCheck(-1, encoding.Damm); !errors.Is(err, ErrSnowflakeNegative) || s != "" {
+		t.Errorf("FormatSnowflakeWithCheck(-1) = %q, %v, want ErrSnowflakeNegative", s, err)
+	}
+	letters := encoding.NewLuhn("abcdefghij")
+	if _, err := FormatSnowflakeWithCheck(12, letters); !errors.Is(err, encoding.ErrInvalidCharacter) {
+		t.Errorf("FormatSnowflakeWithCheck() with a non-decimal checksum error = %v, want ErrInvalidCharacter", err)
+	}
+
+	malformed := []string{"", "7", "-12", "+12", "12a4", "99999999999999999999", "123#"}
+	for _, s := range malformed {
+		_, err := ParseSnowflakeWithCheck(encoding.Damm, s)
+		if !errors.Is(err, ErrInvalidSnowflakeID) || !errors.Is(err, encoding.ErrMalformed) {
+			t.Errorf("ParseSnowflakeWithCheck(%q) expected malformed error, got %v", s, err)
+		}
+	}
+}
+
+func TestUUIDWithCheck(t *testing.T) {
+	enc := encoding.WithChecksum(encoding.Crockford32, encoding.CrockfordMod37)
+	uuid, _ := NewUUIDv7()
+
+	s := uuid.Encode(enc)
+	if len(s) != 27 {
+		t.Fatalf("Encoded length = %d, want 27", len(s))
+	}
+	parsed, err := ParseUUIDWith(enc, s)
+	if err != nil || parsed != uuid {
+		t.Fatalf("ParseUUIDWith(%q) = %v, %v", s, parsed, err)
+	}
+
+	typo := []byte(s)
+	if typo[10] == 'A' {
+		typo[10] = 'B'
+	} else {
+		typo[10] = 'A'
+	}
+	if _, err := ParseUUIDWith(enc, string(typo)); !errors.Is(err, encoding.ErrChecksumMismatch) {
+		t.Errorf("ParseUUIDWith(%q) expected ErrChecksumMismatch, got %v", typo, err)
+	}
+}
@@ -0,0 +1,99 @@
+package idgen
+
+// IDRange is a compact representation of consecutive Snowflake IDs
+// reserved with Snowflake.ReserveRange.
+//
+// IDs in a range are strictly increasing but not always contiguous integers:
+// when the sequence of a millisecond is exhausted, the next ID moves to
+// sequence 0 of the following millisecond.
+//
+// IDRange also works as an iterator: Next consumes the range from the front.
+//
+// Example:
+//
+//	r := generator.ReserveRange(100)
+//	for id, ok := r.Next(); ok; id, ok = r.Next() {
+//	    fmt.Println(id)
+//	}
+type IDRange struct {
+	// Start is the first ID in the range
+	Start int64
+
+	// Count is the number of IDs in the range
+	Count int
+
+	// layout of the generator that reserved the range; the zero value means DefaultLayout
+	layout Layout
+}
+
+// fields returns the sequence width and the timestamp position of the range IDs
+func (r IDRange) fields() (seqBits uint, tsShift uint) {
+	if r.layout.TimestampBits == 0 {
+		return sequenceBits, timestampShift
+	}
+	return r.layout.SequenceBits, r.layout.timestampShift()
+}
+
+// Len returns the number of IDs in the range.
+func (r IDRange) Len() int {
+	return r.Count
+}
+
+// At returns the i-th ID of the range.
+// It panics if i is out of range.
+func (r IDRange) At(i int) int64 {
+	if i < 0 || i >= r.Count {
+		panic("idgen: IDRange index out of range")
+	}
+
+	seqBits, tsShift := r.fields()
+	seqMask := int64(fieldMask(seqBits))
+	position := (r.Start & seqMask) + int64(i)
+	base := r.Start &^ seqMask
+	return base + (position>>seqBits)<<tsShift + position&seqMask
+}
+
+// Next returns the first ID of the range and removes it from the range.
+// The boolean is false when the range is empty.
+func (r *IDRange) Next() (int64, bool) {
+	if r.Count <= 0 {
+		return 0, false
+	}
+
+	id := r.Start
+	r.Count--
+	if r.Count > 0 {
+		seqBits, tsShift := r.fields()
+		seqMask := int64(fieldMask(seqBits))
+		if id&seqMask == seqMask {
+			// Sequence exhausted - move to sequence 0 of the next millisecond
+			r.Start = (id &^ seqMask) + 1<<tsShift
+		} else {
+			r.Start = id + 1
+		}
+	}
+	return id, true
+}
+
+// Fill writes the IDs of the range into dst and returns the number written,
+// which is the minimum of len(dst) and the range length.
+func (r IDRange) Fill(dst []int64) int {
+	n := 0
+	for n < len(dst) {
+		id, ok := r.Next()
+		if !ok {
+			break
+		}
+		dst[n] = id
+		n++
+	}
+	return n
+}
+
+// AppendTo appends the IDs of the range to dst and returns the extended slice.
+func (r IDRange) AppendTo(dst []int64) []int64 {
+	for id, ok := r.Next(); ok; id, ok = r.Next() {
+		dst = append(dst, id)
+	}
+	return dst
+}
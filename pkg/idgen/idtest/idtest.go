@@ -0,0 +1,167 @@
+// Package idtest provides deterministic ID generators for tests and replays.
+//
+// A Generator seeded with the same value always produces the same sequence of
+// Snowflake IDs, UUIDs, ULIDs and TypeIDs. The Set functions swap the generators
+// behind the idgen package-level functions for the duration of a test:
+//
+//	func TestCreateOrder(t *testing.T) {
+//	    idtest.Seed(t, 42) // GenerateSnowflake, NewUUIDv4 and NewUUIDv7 are deterministic until the test ends
+//	    order := CreateOrder()
+//	    snapshot.Match(t, order)
+//	}
+//
+// The package-level generators are process-wide, so tests that swap them must not
+// run in parallel with tests that depend on them.
+package idtest
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen"
+)
+
+// Start is the time of the logical clock of a new Generator: the idgen DefaultEpoch,
+// 2025-01-01T00:00:00Z
+var Start = time.UnixMilli(idgen.DefaultEpoch).UTC()
+
+// Generator produces deterministic IDs from a seed.
+// Every ID advances a logical clock by one millisecond, so time-ordered IDs
+// are strictly increasing in generation order, whatever their kind.
+// It is safe for concurrent use, but concurrent callers get IDs in an unspecified order.
+type Generator struct {
+	mu      sync.Mutex
+	rng     *rand.Rand
+	elapsed int64 // milliseconds since Start
+}
+
+// New creates a Generator seeded with seed.
+//
+// Example:
+//
+//	gen := idtest.New(42)
+//	id := gen.Snowflake() // the same ID on every run
+func New(seed int64) *Generator {
+	return &Generator{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Now returns the current time of the logical clock
+func (g *Generator) Now() time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return Start.Add(time.Duration(g.elapsed) * time.Millisecond)
+}
+
+// tick advances the logical clock, fills random from the seeded source,
+// and returns the new clock value in milliseconds since Start
+func (g *Generator) tick(random []byte) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.elapsed++
+	g.rng.Read(random)
+	return g.elapsed
+}
+
+// Snowflake returns the next Snowflake ID. It decodes with idgen.DefaultLayout and
+// idgen.DefaultEpoch; the process ID, worker ID and sequence are random.
+func (g *Generator) Snowflake() int64 {
+	var random [8]byte
+	elapsed := g.tick(random[:])
+	node := int64(binary.BigEndian.Uint64(random[:]) & (1<<22 - 1))
+	return elapsed<<22 | node
+}
+
+// UUIDv4 returns the next random (version 4) UUID
+func (g *Generator) UUIDv4() idgen.UUID {
+	var uuid idgen.UUID
+	g.tick(uuid[:])
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return uuid
+}
+
+// UUIDv7 returns the next time-ordered (version 7) UUID, timestamped with the logical clock
+func (g *Generator) UUIDv7() idgen.UUID {
+	var uuid idgen.UUID
+	var random [10]byte
+	elapsed := g.tick(random[:])
+	binary.BigEndian.PutUint64(uuid[0:8], uint64(Start.UnixMilli()+elapsed)<<16)
+	copy(uuid[6:], random[:])
+	uuid[6] = (uuid[6] & 0x0f) | 0x70
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return uuid
+}
+
+// ULID returns the next ULID, with the same bits as UUIDv7
+func (g *Generator) ULID() idgen.ULID {
+	return idgen.ULIDFromUUID(g.UUIDv7())
+}
+
+// TypeID returns the next TypeID with prefix P, backed by g.UUIDv7
+func TypeID[P idgen.Prefix](g *Generator) idgen.TypeID[P] {
+	return idgen.TypeIDFromUUID[P](g.UUIDv7())
+}
+
+// SequentialUUID produces UUIDs counting up from 00000000-0000-0000-0000-000000000001.
+// The zero value is ready to use and safe for concurrent use.
+type SequentialUUID struct {
+	n atomic.Uint64
+}
+
+// Next returns the next UUID in the sequence
+func (s *SequentialUUID) Next() idgen.UUID {
+	var uuid idgen.UUID
+	binary.BigEndian.PutUint64(uuid[8:], s.n.Add(1))
+	return uuid
+}
+
+// SetSnowflake makes idgen.GenerateSnowflake and idgen.GenerateSnowflakeBatch call fn
+// until the test ends
+func SetSnowflake(t testing.TB, fn func() int64) {
+	t.Helper()
+	t.Cleanup(idgen.OverrideSnowflake(fn))
+}
+
+// SetUUIDv4 makes idgen.NewUUIDv4 and idgen.GenerateUUIDv4 call fn until the test ends
+func SetUUIDv4(t testing.TB, fn func() idgen.UUID) {
+	t.Helper()
+	t.Cleanup(idgen.OverrideUUIDv4(withoutError(fn)))
+}
+
+// SetUUIDv7 makes idgen.NewUUIDv7, idgen.GenerateUUIDv7 and idgen.NewTypeID call fn
+// until the test ends
+func SetUUIDv7(t testing.TB, fn func() idgen.UUID) {
+	t.Helper()
+	t.Cleanup(idgen.OverrideUUIDv7(withoutError(fn)))
+}
+
+// Seed swaps every idgen package-level generator for a Generator seeded with seed
+// until the test ends, and returns it so the test can compute expected IDs.
+//
+// Example:
+//
+//	idtest.Seed(t, 42)
+//	want := idtest.New(42).Snowflake()
+//	if got := idgen.GenerateSnowflake(); got != want { ... }
+func Seed(t testing.TB, seed int64) *Generator {
+	t.Helper()
+	g := New(seed)
+	SetSnowflake(t, g.Snowflake)
+	SetUUIDv4(t, g.UUIDv4)
+	SetUUIDv7(t, g.UUIDv7)
+	return g
+}
+
+// withoutError adapts an infallible UUID function to the idgen override signature
+func withoutError(fn func() idgen.UUID) func() (idgen.UUID, error) {
+	if fn == nil {
+		return nil
+	}
+	return func() (idgen.UUID, error) {
+		return fn(), nil
+	}
+}
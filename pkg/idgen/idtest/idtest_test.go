@@ -0,0 +1,133 @@
+package idtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen"
+)
+
+type orderPrefix struct{}
+
+func (orderPrefix) Prefix() string { return "order" }
+
+func TestGeneratorDeterministic(t *testing.T) {
+	a, b, c := New(42), New(42), New(43)
+
+	for i := 0; i < 100; i++ {
+		sa, sb, sc := a.Snowflake(), b.Snowflake(), c.Snowflake()
+		if sa != sb {
+			t.Fatalf("Snowflake %d differs for the same seed: %d != %d", i, sa, sb)
+		}
+		if i == 0 && sa == sc {
+			t.Errorf("Snowflake is the same for different seeds: %d", sa)
+		}
+
+		if a.UUIDv4() != b.UUIDv4() || a.UUIDv7() != b.UUIDv7() || a.ULID() != b.ULID() {
+			t.Fatalf("UUIDs differ for the same seed at %d", i)
+		}
+		if TypeID[orderPrefix](a) != TypeID[orderPrefix](b) {
+			t.Fatalf("TypeIDs differ for the same seed at %d", i)
+		}
+	}
+}
+
+func TestGeneratorIDs(t *testing.T) {
+	g := New(1)
+
+	prev := int64(0)
+	for i := 0; i < 100; i++ {
+		id := g.Snowflake()
+		if id <= prev {
+			t.Fatalf("Snowflake IDs not increasing: %d <= %d", id, prev)
+		}
+		prev = id
+	}
+	parts, err := idgen.DecodeSnowflake(prev, idgen.DefaultLayout, idgen.DefaultEpoch)
+	if err != nil {
+		t.Fatalf("DecodeSnowflake() error = %v", err)
+	}
+	if want := Start.Add(100 * time.Millisecond); !parts.Time().Equal(want) {
+		t.Errorf("Snowflake time = %v, want %v", parts.Time(), want)
+	}
+
+	v4 := g.UUIDv4()
+	if v4[6]>>4 != 4 || v4[8]>>6 != 0b10 {
+		t.Errorf("UUIDv4() = %s has the wrong version or variant", v4)
+	}
+
+	v7 := g.UUIDv7()
+	if v7[6]>>4 != 7 || v7[8]>>6 != 0b10 {
+		t.Errorf("UUIDv7() = %s has the wrong version or variant", v7)
+	}
+	if got := idgen.ExtractTimeFromUUIDv7(v7); !got.Equal(g.Now()) {
+		t.Errorf("UUIDv7 time = %v, want %v", got, g.Now())
+	}
+
+	if got, want := TypeID[orderPrefix](g).Prefix(), "order"; got != want {
+		t.Errorf("TypeID prefix = %q, want %q", got, want)
+	}
+}
+
+func TestSequentialUUID(t *testing.T) {
+	var seq SequentialUUID
+	want := []string{
+		"00000000-0000-0000-0000-000000000001",
+		"00000000-0000-0000-0000-000000000002",
+		"00000000-0000-0000-0000-000000000003",
+	}
+	for _, w := range want {
+		if got := seq.Next().String(); got != w {
+			t.Errorf("Next() = %s, want %s", got, w)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	want := New(7)
+	wantSnowflake, wantV4, wantV7 := want.Snowflake(), want.UUIDv4(), want.UUIDv7()
+
+	t.Run("swapped", func(t *testing.T) {
+		Seed(t, 7)
+
+		if got := idgen.GenerateSnowflake(); got != wantSnowflake {
+			t.Errorf("GenerateSnowflake() = %d, want %d", got, wantSnowflake)
+		}
+		if got, _ := idgen.NewUUIDv4(); got != wantV4 {
+			t.Errorf("NewUUIDv4() = %s, want %s", got, wantV4)
+		}
+		if got, _ := idgen.GenerateUUIDv7(); got != wantV7.String() {
+			t.Errorf("GenerateUUIDv7() = %s, want %s", got, wantV7)
+		}
+	})
+
+	// The overrides are removed when the subtest ends
+	if got, _ := idgen.NewUUIDv4(); got == wantV4 {
+		t.Error("NewUUIDv4 override was not restored")
+	}
+	if got, _ := idgen.NewUUIDv7(); got.String() == wantV7.String() {
+		t.Error("NewUUIDv7 override was not restored")
+	}
+}
+
+func TestSetNested(t *testing.T) {
+	var outer SequentialUUID
+	SetUUIDv7(t, outer.Next)
+
+	t.Run("inner", func(t *testing.T) {
+		SetUUIDv7(t, func() idgen.UUID { return idgen.UUID{0xff} })
+		if got, _ := idgen.NewUUIDv7(); got != (idgen.UUID{0xff}) {
+			t.Errorf("NewUUIDv7() = %s, want the inner override", got)
+		}
+	})
+
+	if got, _ := idgen.NewUUIDv7(); got.String() != "00000000-0000-0000-0000-000000000001" {
+		t.Errorf("NewUUIDv7() = %s, want the outer override", got)
+	}
+
+	SetSnowflake(t, func() int64 { return 42 })
+	ids := idgen.GenerateSnowflakeBatch(3)
+	if len(ids) != 3 || ids[0] != 42 || ids[2] != 42 {
+		t.Errorf("GenerateSnowflakeBatch() = %v, want [42 42 42]", ids)
+	}
+}
@@ -0,0 +1,50 @@
+package idtest
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInjected is returned by readers created with FailAfter once their budget is spent
+var ErrInjected = errors.New("idtest: injected read failure")
+
+// failingReader reads from r until n bytes were returned, then fails
+type failingReader struct {
+	r io.Reader
+	n int
+}
+
+// FailAfter returns a reader that reads from r and fails with ErrInjected after n bytes.
+// A nil r yields zero bytes. Use it with the SetEntropy methods to exercise error paths:
+//
+//	generator := idgen.NewUUIDv4Generator()
+//	generator.SetEntropy(idtest.FailAfter(nil, 8))
+//	_, err := generator.Generate() // errors.Is(err, idgen.ErrRandomSource) && errors.Is(err, idtest.ErrInjected)
+func FailAfter(r io.Reader, n int) io.Reader {
+	return &failingReader{r: r, n: n}
+}
+
+// Read implements io.Reader
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.n <= 0 {
+		return 0, ErrInjected
+	}
+	if len(p) > f.n {
+		p = p[:f.n]
+	}
+
+	var n int
+	if f.r == nil {
+		clear(p)
+		n = len(p)
+	} else {
+		var err error
+		n, err = f.r.Read(p)
+		if err != nil {
+			f.n -= n
+			return n, err
+		}
+	}
+	f.n -= n
+	return n, nil
+}
@@ -0,0 +1,80 @@
+package idtest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen"
+)
+
+func TestFailAfter(t *testing.T) {
+	r := FailAfter(bytes.NewReader([]byte("abcdef")), 4)
+	got, err := io.ReadAll(r)
+	if !errors.Is(err, ErrInjected) {
+		t.Fatalf("ReadAll() error = %v, want ErrInjected", err)
+	}
+	if string(got) != "abcd" {
+		t.Errorf("ReadAll() = %q, want %q", got, "abcd")
+	}
+
+	zeros := make([]byte, 3)
+	if n, err := io.ReadFull(FailAfter(nil, 3), zeros); n != 3 || err != nil {
+		t.Errorf("ReadFull() = %d, %v", n, err)
+	}
+}
+
+func TestGeneratorsFailWithoutPartialIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		generate func(r io.Reader) (idgen.UUID, error)
+	}{
+		{"UUIDv4", func(r io.Reader) (idgen.UUID, error) {
+			g := idgen.NewUUIDv4Generator()
+			g.SetEntropy(r)
+			return g.Generate()
+		}},
+		{"UUIDv7", func(r io.Reader) (idgen.UUID, error) {
+			g := &idgen.UUIDv7Generator{}
+			g.SetEntropy(r)
+			return g.Generate()
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, n := range []int{0, 1, 7} {
+				uuid, err := tt.generate(FailAfter(bytes.NewReader(bytes.Repeat([]byte{0xff}, 16)), n))
+				if !errors.Is(err, idgen.ErrRandomSource) || !errors.Is(err, ErrInjected) {
+					t.Errorf("FailAfter(%d): error = %v, want ErrRandomSource wrapping ErrInjected", n, err)
+				}
+				if uuid != (idgen.UUID{}) {
+					t.Errorf("FailAfter(%d): got partial UUID %s", n, uuid)
+				}
+			}
+
+			if _, err := tt.generate(FailAfter(nil, 16)); err != nil {
+				t.Errorf("Generate() with enough entropy: error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPackageLevelEntropy(t *testing.T) {
+	idgen.SetUUIDv4Entropy(FailAfter(nil, 0))
+	defer idgen.SetUUIDv4Entropy(nil)
+
+	if _, err := idgen.GenerateUUIDv4(); !errors.Is(err, ErrInjected) {
+		t.Errorf("GenerateUUIDv4() error = %v, want ErrInjected", err)
+	}
+	ids, err := idgen.GenerateUUIDv4Batch(3)
+	if !errors.Is(err, idgen.ErrRandomSource) || ids != nil {
+		t.Errorf("GenerateUUIDv4Batch() = %v, %v, want nil and ErrRandomSource", ids, err)
+	}
+
+	idgen.SetUUIDv4Entropy(nil)
+	if _, err := idgen.GenerateUUIDv4(); err != nil {
+		t.Errorf("GenerateUUIDv4() after restore: error = %v", err)
+	}
+}
@@ -0,0 +1,357 @@
+package idgen
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen/encoding"
+)
+
+// Obfuscator errors
+var (
+	// ErrInvalidObfuscatorKey is returned when an Obfuscator secret or key ID is invalid
+	ErrInvalidObfuscatorKey = errors.New("invalid obfuscator key")
+
+	// ErrUnknownKeyID is returned when a public ID was encoded with a key that is not in the keyring
+	ErrUnknownKeyID = errors.New("unknown obfuscator key ID")
+
+	// ErrInvalidPublicID is returned when a public ID is malformed or out of range
+	ErrInvalidPublicID = errors.New("invalid public ID")
+)
+
+const (
+	// MinObfuscatorSecretSize is the minimum length of an Obfuscator secret in bytes
+	MinObfuscatorSecretSize = 16
+
+	// MaxObfuscatorKeyID is the largest key ID; key IDs are encoded as one base62 character
+	MaxObfuscatorKeyID = 61
+
+	// obfuscatorRounds is the number of Feistel rounds
+	obfuscatorRounds = 8
+
+	// publicIDValueLen is the number of base62 characters of an obfuscated 63-bit value
+	publicIDValueLen = 11
+
+	// Domain separation between the 64-bit and the 128-bit permutations
+	domain64  = 0x40
+	domain128 = 0x80
+)
+
+// Obfuscator hides the order and volume of Snowflake IDs and UUIDs exposed to the public
+// by permuting them with a keyed Feistel network.
+//
+// The permutation is a bijection: every ID maps to exactly one obfuscated ID and
+// Decode(Encode(id)) == id for every valid input. Snowflake IDs are permuted within
+// [0, 2^63), so obfuscated IDs are still positive int64 values; UUIDs are permuted
+// over all 128 bits, so obfuscated UUIDs are not valid UUID v4/v7 values.
+//
+// The round function is AES-128 keyed from the secret. Without the secret, consecutive
+// IDs map to unrelated values, which hides creation time and generation rate.
+// This is obfuscation, not encryption with integrity: anyone can submit arbitrary
+// public IDs, so decoded IDs must still be looked up and authorized as usual.
+//
+// An Obfuscator is safe for concurrent use.
+type Obfuscator struct {
+	keyID uint8
+	block cipher.Block
+}
+
+// NewObfuscator creates an Obfuscator from a secret.
+//
+// Parameters:
+//   - keyID: Identifier of the secret (0-61), written as the first character of public IDs
+//     so that secrets can be rotated with an ObfuscatorKeyring
+//   - secret: Secret key material, at least MinObfuscatorSecretSize bytes
+//
+// Returns:
+//   - *Obfuscator: A new obfuscator
+//   - error: ErrInvalidObfuscatorKey if the key ID or the secret is invalid
+//
+// Example:
+//
+//	obfuscator, err := idgen.NewObfuscator(1, []byte(os.Getenv("ID_SECRET")))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	publicID, _ := obfuscator.EncodeString(orderID) // e.g. "1cHq0xV3pTk2"
+func NewObfuscator(keyID uint8, secret []byte) (*Obfuscator, error) {
+	if keyID > MaxObfuscatorKeyID {
+		return nil, fmt.Errorf("%w: key ID %d is greater than %d", ErrInvalidObfuscatorKey, keyID, MaxObfuscatorKeyID)
+	}
+	if len(secret) < MinObfuscatorSecretSize {
+		return nil, fmt.Errorf("%w: secret must be at least %d bytes", ErrInvalidObfuscatorKey, MinObfuscatorSecretSize)
+	}
+
+	// Derive a fixed-size AES key from secrets of any length
+	h := sha256.New()
+	h.Write([]byte("idgen obfuscator v1"))
+	h.Write(secret)
+	key := h.Sum(nil)
+
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidObfuscatorKey, err)
+	}
+	return &Obfuscator{keyID: keyID, block: block}, nil
+}
+
+// KeyID returns the key identifier of the obfuscator.
+func (o *Obfuscator) KeyID() uint8 {
+	return o.keyID
+}
+
+// Encode maps a Snowflake ID to its obfuscated form.
+//
+// Parameters:
+//   - id: A non-negative ID
+//
+// Returns:
+//   - int64: The obfuscated ID, also non-negative
+//   - error: ErrInvalidPublicID if id is negative
+func (o *Obfuscator) Encode(id int64) (int64, error) {
+	if id < 0 {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidPublicID, ErrSnowflakeNegative)
+	}
+
+	// Cycle walking: the Feistel network permutes 64 bits,
+	// repeat until the result falls back into the 63-bit domain
+	var scratch [aes.BlockSize]byte
+	v := uint64(id)
+	for {
+		v = o.encrypt64(v, scratch[:])
+		if v>>63 == 0 {
+			return int64(v), nil
+		}
+	}
+}
+
+// Decode maps an obfuscated ID back to the original Snowflake ID.
+//
+// Parameters:
+//   - id: An ID returned by Encode
+//
+// Returns:
+//   - int64: The original ID
+//   - error: ErrInvalidPublicID if id is negative
+func (o *Obfuscator) Decode(id int64) (int64, error) {
+	if id < 0 {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidPublicID, ErrSnowflakeNegative)
+	}
+
+	var scratch [aes.BlockSize]byte
+	v := uint64(id)
+	for {
+		v = o.decrypt64(v, scratch[:])
+		if v>>63 == 0 {
+			return int64(v), nil
+		}
+	}
+}
+
+// EncodeUUID maps a UUID to its obfuscated form.
+func (o *Obfuscator) EncodeUUID(u UUID) UUID {
+	l := binary.BigEndian.Uint64(u[:8])
+	r := binary.BigEndian.Uint64(u[8:])
+	var scratch [aes.BlockSize]byte
+	for round := 0; round < obfuscatorRounds; round++ {
+		l, r = r, l^o.round64(round, r, scratch[:])
+	}
+
+	var out UUID
+	binary.BigEndian.PutUint64(out[:8], l)
+	binary.BigEndian.PutUint64(out[8:], r)
+	return out
+}
+
+// DecodeUUID maps an obfuscated UUID back to the original UUID.
+func (o *Obfuscator) DecodeUUID(u UUID) UUID {
+	l := binary.BigEndian.Uint64(u[:8])
+	r := binary.BigEndian.Uint64(u[8:])
+	var scratch [aes.BlockSize]byte
+	for round := obfuscatorRounds - 1; round >= 0; round-- {
+		l, r = r^o.round64(round, l, scratch[:]), l
+	}
+
+	var out UUID
+	binary.BigEndian.PutUint64(out[:8], l)
+	binary.BigEndian.PutUint64(out[8:], r)
+	return out
+}
+
+// EncodeString obfuscates id and formats it as a 12-character public ID:
+// one base62 character for the key ID followed by 11 base62 characters.
+//
+// Parameters:
+//   - id: A non-negative ID
+//
+// Returns:
+//   - string: The public ID
+//   - error: ErrInvalidPublicID if id is negative
+func (o *Obfuscator) EncodeString(id int64) (string, error) {
+	encoded, err := o.Encode(id)
+	if err != nil {
+		return "", err
+	}
+
+	var value [8]byte
+	binary.BigEndian.PutUint64(value[:], uint64(encoded))
+
+	var buf [1 + publicIDValueLen]byte
+	buf[0] = encoding.Base62Alphabet[o.keyID]
+	encoding.Base62.Encode(buf[1:], value[:])
+	return string(buf[:]), nil
+}
+
+// DecodeString parses a public ID produced by EncodeString and returns the original ID.
+//
+// Returns:
+//   - int64: The original ID
+//   - error: ErrUnknownKeyID if the public ID was encoded with another key,
+//     ErrInvalidPublicID if it is malformed
+func (o *Obfuscator) DecodeString(s string) (int64, error) {
+	keyID, value, err := parsePublicID(s)
+	if err != nil {
+		return 0, err
+	}
+	if keyID != o.keyID {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownKeyID, keyID)
+	}
+	return o.Decode(value)
+}
+
+// encrypt64 applies the Feistel network to a 64-bit block.
+// scratch is an AES block-sized buffer, shared across rounds to avoid allocations.
+func (o *Obfuscator) encrypt64(v uint64, scratch []byte) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+	for round := 0; round < obfuscatorRounds; round++ {
+		l, r = r, l^o.round32(round, r, scratch)
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+// decrypt64 inverts encrypt64
+func (o *Obfuscator) decrypt64(v uint64, scratch []byte) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+	for round := obfuscatorRounds - 1; round >= 0; round-- {
+		l, r = r^o.round32(round, l, scratch), l
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+// round32 is the round function of the 64-bit network
+func (o *Obfuscator) round32(round int, half uint32, scratch []byte) uint32 {
+	clear(scratch)
+	scratch[0] = domain64
+	scratch[1] = byte(round)
+	binary.BigEndian.PutUint32(scratch[2:], half)
+	o.block.Encrypt(scratch, scratch)
+	return binary.BigEndian.Uint32(scratch)
+}
+
+// round64 is the round function of the 128-bit network
+func (o *Obfuscator) round64(round int, half uint64, scratch []byte) uint64 {
+	clear(scratch)
+	scratch[0] = domain128
+	scratch[1] = byte(round)
+	binary.BigEndian.PutUint64(scratch[2:], half)
+	o.block.Encrypt(scratch, scratch)
+	return binary.BigEndian.Uint64(scratch)
+}
+
+// ObfuscatorKeyring supports secret rotation: public IDs are always encoded with
+// the primary key and decoded with whichever key their prefix names.
+//
+// Example:
+//
+//	oldKey, _ := idgen.NewObfuscator(1, oldSecret)
+//	newKey, _ := idgen.NewObfuscator(2, newSecret)
+//	keyring, _ := idgen.NewObfuscatorKeyring(newKey, oldKey)
+//
+//	publicID, _ := keyring.EncodeString(id) // encoded with key 2
+//	id, err := keyring.DecodeString(legacyPublicID) // still decodes key 1 IDs
+type ObfuscatorKeyring struct {
+	primary *Obfuscator
+	keys    map[uint8]*Obfuscator
+}
+
+// NewObfuscatorKeyring creates a keyring that encodes with primary and decodes with
+// primary or any of the other obfuscators.
+//
+// Parameters:
+//   - primary: The obfuscator used to encode new public IDs
+//   - others: Obfuscators for previous secrets that must still decode
+//
+// Returns:
+//   - *ObfuscatorKeyring: A new keyring
+//   - error: ErrInvalidObfuscatorKey if primary is nil or two obfuscators share a key ID
+func NewObfuscatorKeyring(primary *Obfuscator, others ...*Obfuscator) (*ObfuscatorKeyring, error) {
+	if primary == nil {
+		return nil, fmt.Errorf("%w: primary obfuscator is nil", ErrInvalidObfuscatorKey)
+	}
+
+	k := &ObfuscatorKeyring{primary: primary, keys: map[uint8]*Obfuscator{primary.keyID: primary}}
+	for _, o := range others {
+		if o == nil {
+			continue
+		}
+		if _, ok := k.keys[o.keyID]; ok {
+			return nil, fmt.Errorf("%w: duplicate key ID %d", ErrInvalidObfuscatorKey, o.keyID)
+		}
+		k.keys[o.keyID] = o
+	}
+	return k, nil
+}
+
+// Primary returns the obfuscator used for encoding.
+func (k *ObfuscatorKeyring) Primary() *Obfuscator {
+	return k.primary
+}
+
+// EncodeString encodes id with the primary obfuscator.
+func (k *ObfuscatorKeyring) EncodeString(id int64) (string, error) {
+	return k.primary.EncodeString(id)
+}
+
+// DecodeString decodes a public ID with the obfuscator named by its key ID prefix.
+//
+// Returns:
+//   - int64: The original ID
+//   - error: ErrUnknownKeyID if the key is not in the keyring, ErrInvalidPublicID if s is malformed
+func (k *ObfuscatorKeyring) DecodeString(s string) (int64, error) {
+	keyID, value, err := parsePublicID(s)
+	if err != nil {
+		return 0, err
+	}
+	o, ok := k.keys[keyID]
+	if !ok {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownKeyID, keyID)
+	}
+	return o.Decode(value)
+}
+
+// parsePublicID splits a public ID into its key ID and obfuscated value
+func parsePublicID(s string) (keyID uint8, value int64, err error) {
+	if len(s) != 1+publicIDValueLen {
+		return 0, 0, fmt.Errorf("%w: length %d, want %d", ErrInvalidPublicID, len(s), 1+publicIDValueLen)
+	}
+
+	k := strings.IndexByte(encoding.Base62Alphabet, s[0])
+	if k < 0 {
+		return 0, 0, fmt.Errorf("%w: invalid key ID %q", ErrInvalidPublicID, s[0])
+	}
+
+	var b [8]byte
+	if err := encoding.Base62.Decode(b[:], []byte(s[1:])); err != nil {
+		return 0, 0, fmt.Errorf("%w: %w", ErrInvalidPublicID, err)
+	}
+	v := binary.BigEndian.Uint64(b[:])
+	if v>>63 != 0 {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidPublicID, s)
+	}
+	return uint8(k), int64(v), nil
+}
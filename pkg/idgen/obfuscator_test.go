@@ -0,0 +1,249 @@
+package idgen
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestNewObfuscator(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyID   uint8
+		secret  []byte
+		wantErr bool
+	}{
+		{"valid", 1, testSecret, false},
+		{"largest key ID", MaxObfuscatorKeyID, testSecret, false},
+		{"key ID too large", MaxObfuscatorKeyID + 1, testSecret, true},
+		{"short secret", 1, []byte("short"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := NewObfuscator(tt.keyID, tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewObfuscator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidObfuscatorKey) {
+					t.Errorf("Expected ErrInvalidObfuscatorKey, got %v", err)
+				}
+				return
+			}
+			if o.KeyID() != tt.keyID {
+				t.Errorf("KeyID() = %d, want %d", o.KeyID(), tt.keyID)
+			}
+		})
+	}
+}
+
+// TestObfuscatorRoundTrip checks Decode(Encode(id)) == id over many values.
+// The full run covers millions of IDs; -short keeps it fast.
+func TestObfuscatorRoundTrip(t *testing.T) {
+	o, _ := NewObfuscator(1, testSecret)
+
+	iterations := 1 << 20
+	if testing.Short() {
+		iterations = 1 << 14
+	}
+
+	generator, _ := New(1, 1)
+	rng := rand.New(rand.NewSource(1))
+	edges := []int64{0, 1, math.MaxInt64, math.MaxInt64 - 1, 1 << 62, generator.Generate()}
+
+	check := func(id int64) {
+		encoded, err := o.Encode(id)
+		if err != nil {
+			t.Fatalf("Encode(%d) error = %v", id, err)
+		}
+		if encoded < 0 {
+			t.Fatalf("Encode(%d) = %d, outside the 63-bit domain", id, encoded)
+		}
+		decoded, err := o.Decode(encoded)
+		if err != nil {
+			t.Fatalf("Decode(%d) error = %v", encoded, err)
+		}
+		if decoded != id {
+			t.Fatalf("Decode(Encode(%d)) = %d", id, decoded)
+		}
+	}
+
+	for _, id := range edges {
+		check(id)
+	}
+	for i := 0; i < iterations; i++ {
+		check(rng.Int63())
+	}
+
+	// Consecutive IDs, the case obfuscation exists for
+	start := generator.Generate()
+	for i := int64(0); i < int64(iterations/4); i++ {
+		check(start + i)
+	}
+}
+
+func TestObfuscatorUUIDRoundTrip(t *testing.T) {
+	o, _ := NewObfuscator(1, testSecret)
+
+	iterations := 1 << 18
+	if testing.Short() {
+		iterations = 1 << 12
+	}
+
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < iterations; i++ {
+		var u UUID
+		rng.Read(u[:])
+		if got := o.DecodeUUID(o.EncodeUUID(u)); got != u {
+			t.Fatalf("DecodeUUID(EncodeUUID(%s)) = %s", u, got)
+		}
+	}
+
+	v7, _ := NewUUIDv7()
+	if o.EncodeUUID(v7) == v7 {
+		t.Errorf("EncodeUUID() returned its input")
+	}
+}
+
+func TestObfuscatorHidesOrder(t *testing.T) {
+	o, _ := NewObfuscator(1, testSecret)
+	generator, _ := New(1, 1)
+	ids := generator.GenerateBatch(1000)
+
+	// Consecutive inputs must not produce mostly increasing outputs
+	increasing := 0
+	seen := make(map[int64]bool, len(ids))
+	prev, _ := o.Encode(ids[0])
+	for _, id := range ids[1:] {
+		encoded, _ := o.Encode(id)
+		if encoded > prev {
+			increasing++
+		}
+		if seen[encoded] {
+			t.Fatalf("Duplicate obfuscated ID %d", encoded)
+		}
+		seen[encoded] = true
+		prev = encoded
+	}
+	if increasing < 400 || increasing > 600 {
+		t.Errorf("%d of 999 consecutive outputs increase, want about half", increasing)
+	}
+
+	// Different secrets give different permutations
+	other, _ := NewObfuscator(1, []byte("another secret of sixteen bytes"))
+	a, _ := o.Encode(ids[0])
+	b, _ := other.Encode(ids[0])
+	if a == b {
+		t.Errorf("Different secrets produced the same output %d", a)
+	}
+}
+
+func TestObfuscatorNegative(t *testing.T) {
+	o, _ := NewObfuscator(1, testSecret)
+	if _, err := o.Encode(-1); !errors.Is(err, ErrInvalidPublicID) {
+		t.Errorf("Encode(-1) error = %v, want ErrInvalidPublicID", err)
+	}
+	if _, err := o.Decode(-1); !errors.Is(err, ErrInvalidPublicID) {
+		t.Errorf("Decode(-1) error = %v, want ErrInvalidPublicID", err)
+	}
+}
+
+func TestObfuscatorString(t *testing.T) {
+	o, _ := NewObfuscator(7, testSecret)
+	generator, _ := New(1, 1)
+	id := generator.Generate()
+
+	public, err := o.EncodeString(id)
+	if err != nil {
+		t.Fatalf("EncodeString() error = %v", err)
+	}
+	if len(public) != 12 || public[0] != '7' {
+		t.Errorf("EncodeString() = %q, want 12 characters starting with key ID 7", public)
+	}
+
+	decoded, err := o.DecodeString(public)
+	if err != nil || decoded != id {
+		t.Errorf("DecodeString(%q) = %d, %v, want %d", public, decoded, err, id)
+	}
+
+	// Largest value still fits in 11 base62 characters
+	o0, _ := NewObfuscator(0, testSecret)
+	for _, v := range []int64{0, math.MaxInt64} {
+		encoded, _ := o0.Encode(v)
+		if s, _ := o0.EncodeString(v); len(s) != 12 {
+			t.Errorf("EncodeString(%d) = %q (encoded %d)", v, s, encoded)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{"other key", "8" + public[1:], ErrUnknownKeyID},
+		{"too short", public[:11], ErrInvalidPublicID},
+		{"invalid character", public[:11] + "-", ErrInvalidPublicID},
+		{"overflow", "7" + strings.Repeat("z", 11), ErrInvalidPublicID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := o.DecodeString(tt.input); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeString(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestObfuscatorKeyring(t *testing.T) {
+	oldKey, _ := NewObfuscator(1, testSecret)
+	newKey, _ := NewObfuscator(2, []byte("rotated secret, sixteen bytes+"))
+
+	legacy, _ := oldKey.EncodeString(42)
+
+	keyring, err := NewObfuscatorKeyring(newKey, oldKey)
+	if err != nil {
+		t.Fatalf("NewObfuscatorKeyring() error = %v", err)
+	}
+	if keyring.Primary() != newKey {
+		t.Errorf("Primary() is not the new key")
+	}
+
+	public, _ := keyring.EncodeString(42)
+	if public[0] != '2' {
+		t.Errorf("EncodeString() = %q, want key ID 2", public)
+	}
+	for _, s := range []string{public, legacy} {
+		if id, err := keyring.DecodeString(s); err != nil || id != 42 {
+			t.Errorf("DecodeString(%q) = %d, %v, want 42", s, id, err)
+		}
+	}
+
+	unknown, _ := NewObfuscator(3, testSecret)
+	foreign, _ := unknown.EncodeString(42)
+	if _, err := keyring.DecodeString(foreign); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("DecodeString() with unknown key error = %v, want ErrUnknownKeyID", err)
+	}
+
+	if _, err := NewObfuscatorKeyring(oldKey, unknown, oldKey); !errors.Is(err, ErrInvalidObfuscatorKey) {
+		t.Errorf("Duplicate key IDs error = %v, want ErrInvalidObfuscatorKey", err)
+	}
+	if _, err := NewObfuscatorKeyring(nil); !errors.Is(err, ErrInvalidObfuscatorKey) {
+		t.Errorf("Nil primary error = %v, want ErrInvalidObfuscatorKey", err)
+	}
+}
+
+func BenchmarkObfuscatorEncode(b *testing.B) {
+	o, _ := NewObfuscator(1, testSecret)
+	generator, _ := New(1, 1)
+	id := generator.Generate()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.Encode(id + int64(i))
+	}
+}
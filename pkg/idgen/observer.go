@@ -0,0 +1,152 @@
+package idgen
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Generator names reported to observers
+const (
+	GeneratorSnowflake       = "snowflake"
+	GeneratorAtomicSnowflake = "atomic_snowflake"
+	GeneratorUUIDv7          = "uuidv7"
+)
+
+// Observer receives events from the time-based generators in this package.
+//
+// Observers are called synchronously, sometimes while the generator holds its lock,
+// so implementations must be fast, non-blocking and safe for concurrent use.
+// The generator argument is one of the Generator* constants.
+type Observer interface {
+	// Generated is called after n IDs were issued
+	Generated(generator string, n int)
+
+	// SequenceExhausted is called when all sequence numbers of a millisecond were used
+	SequenceExhausted(generator string)
+
+	// Waited is called after the generator slept waiting for the clock
+	Waited(generator string, d time.Duration)
+
+	// ClockRegression is called when the system clock is observed moving backwards by delta
+	ClockRegression(generator string, delta time.Duration)
+}
+
+// NopObserver is an Observer that ignores every event. It is the default observer.
+type NopObserver struct{}
+
+// Generated implements Observer
+func (NopObserver) Generated(string, int) {}
+
+// SequenceExhausted implements Observer
+func (NopObserver) SequenceExhausted(string) {}
+
+// Waited implements Observer
+func (NopObserver) Waited(string, time.Duration) {}
+
+// ClockRegression implements Observer
+func (NopObserver) ClockRegression(string, time.Duration) {}
+
+// MultiObserver returns an Observer that forwards every event to all observers in order.
+//
+// Example:
+//
+//	generator.SetObserver(idgen.MultiObserver(expvarObserver, promObserver))
+func MultiObserver(observers ...Observer) Observer {
+	list := make(multiObserver, 0, len(observers))
+	for _, o := range observers {
+		if o != nil {
+			list = append(list, o)
+		}
+	}
+	return list
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) Generated(generator string, n int) {
+	for _, o := range m {
+		o.Generated(generator, n)
+	}
+}
+
+func (m multiObserver) SequenceExhausted(generator string) {
+	for _, o := range m {
+		o.SequenceExhausted(generator)
+	}
+}
+
+func (m multiObserver) Waited(generator string, d time.Duration) {
+	for _, o := range m {
+		o.Waited(generator, d)
+	}
+}
+
+func (m multiObserver) ClockRegression(generator string, delta time.Duration) {
+	for _, o := range m {
+		o.ClockRegression(generator, delta)
+	}
+}
+
+// hooks holds the optional instrumentation attached to a generator
+type hooks struct {
+	observer Observer
+	logger   *slog.Logger
+}
+
+// log emits a structured record when a logger is attached
+func (h *hooks) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if h.logger == nil {
+		return
+	}
+	h.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+var defaultHooks = hooks{observer: NopObserver{}}
+
+// instrumented is embedded in generators to hold their hooks.
+// Hooks are replaced copy-on-write, so they can be changed while the generator is in use.
+type instrumented struct {
+	hooks atomic.Pointer[hooks]
+}
+
+// loadHooks returns the current hooks, never nil
+func (i *instrumented) loadHooks() *hooks {
+	if h := i.hooks.Load(); h != nil {
+		return h
+	}
+	return &defaultHooks
+}
+
+// updateHooks applies fn to a copy of the current hooks and stores the result
+func (i *instrumented) updateHooks(fn func(h *hooks)) {
+	for {
+		old := i.hooks.Load()
+		next := defaultHooks
+		if old != nil {
+			next = *old
+		}
+		fn(&next)
+		if i.hooks.CompareAndSwap(old, &next) {
+			return
+		}
+	}
+}
+
+// setObserver attaches o, or restores the no-op observer when o is nil
+func (i *instrumented) setObserver(o Observer) {
+	if o == nil {
+		o = NopObserver{}
+	}
+	i.updateHooks(func(h *hooks) {
+		h.observer = o
+	})
+}
+
+// setLogger attaches l, or disables logging when l is nil
+func (i *instrumented) setLogger(l *slog.Logger) {
+	i.updateHooks(func(h *hooks) {
+		h.logger = l
+	})
+}
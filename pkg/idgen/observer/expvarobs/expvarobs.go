@@ -0,0 +1,71 @@
+// Package expvarobs publishes idgen generator events through the expvar package.
+//
+// It lives outside the core idgen package because importing expvar registers
+// /debug/vars on http.DefaultServeMux, which only programs that opt in should get.
+package expvarobs
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen"
+)
+
+// Observer is an idgen.Observer that publishes counters through the expvar package,
+// so they show up under /debug/vars when expvar's HTTP handler is registered.
+//
+// Counters are keyed "<generator>.<event>":
+//   - generated: IDs issued
+//   - sequence_exhausted: milliseconds whose sequence ran out
+//   - waits / wait_ns: clock waits and total time spent waiting
+//   - clock_regressions / clock_regression_ns: observed regressions and their total size
+type Observer struct {
+	vars *expvar.Map
+}
+
+var _ idgen.Observer = (*Observer)(nil)
+
+// New returns an Observer publishing its counters as the expvar map name.
+// Calling it again with the same name reuses the already published map.
+// It panics if name is already published as a different kind of expvar.Var.
+//
+// Example:
+//
+//	generator.SetObserver(expvarobs.New("idgen"))
+func New(name string) *Observer {
+	if v := expvar.Get(name); v != nil {
+		m, ok := v.(*expvar.Map)
+		if !ok {
+			panic("expvarobs: expvar " + name + " is not an *expvar.Map")
+		}
+		return &Observer{vars: m}
+	}
+	return &Observer{vars: expvar.NewMap(name)}
+}
+
+// Map returns the published expvar map.
+func (o *Observer) Map() *expvar.Map {
+	return o.vars
+}
+
+// Generated implements idgen.Observer
+func (o *Observer) Generated(generator string, n int) {
+	o.vars.Add(generator+".generated", int64(n))
+}
+
+// SequenceExhausted implements idgen.Observer
+func (o *Observer) SequenceExhausted(generator string) {
+	o.vars.Add(generator+".sequence_exhausted", 1)
+}
+
+// Waited implements idgen.Observer
+func (o *Observer) Waited(generator string, d time.Duration) {
+	o.vars.Add(generator+".waits", 1)
+	o.vars.Add(generator+".wait_ns", int64(d))
+}
+
+// ClockRegression implements idgen.Observer
+func (o *Observer) ClockRegression(generator string, delta time.Duration) {
+	o.vars.Add(generator+".clock_regressions", 1)
+	o.vars.Add(generator+".clock_regression_ns", int64(delta))
+}
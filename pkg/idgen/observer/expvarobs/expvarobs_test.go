@@ -0,0 +1,51 @@
+package expvarobs
+
+import (
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen"
+)
+
+func TestObserver(t *testing.T) {
+	observer := New("idgen_test_expvar")
+	observer.Map().Init() // expvar is global, start from a clean map
+	observer.Generated(idgen.GeneratorSnowflake, 3)
+	observer.Waited(idgen.GeneratorSnowflake, 2*time.Millisecond)
+	observer.ClockRegression(idgen.GeneratorUUIDv7, time.Millisecond)
+
+	// Reuses the published map
+	again := New("idgen_test_expvar")
+	again.Generated(idgen.GeneratorSnowflake, 1)
+
+	vars := observer.Map()
+	tests := map[string]string{
+		"snowflake.generated":           "4",
+		"snowflake.waits":               "1",
+		"snowflake.wait_ns":             "2000000",
+		"uuidv7.clock_regressions":      "1",
+		"uuidv7.clock_regression_ns":    "1000000",
+		"atomic_snowflake.generated":    "",
+		"snowflake.sequence_exhausted":  "",
+		"uuidv7.generated":              "",
+		"snowflake.clock_regressions":   "",
+		"snowflake.clock_regression_ns": "",
+	}
+	for key, want := range tests {
+		v := vars.Get(key)
+		if want == "" {
+			if v != nil {
+				t.Errorf("%s = %s, want unset", key, v)
+			}
+			continue
+		}
+		if v == nil || v.String() != want {
+			t.Errorf("%s = %v, want %s", key, v, want)
+		}
+	}
+
+	if expvar.Get("idgen_test_expvar") != vars {
+		t.Error("Observer map is not published under its name")
+	}
+}
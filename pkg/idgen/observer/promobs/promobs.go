@@ -0,0 +1,181 @@
+// Package promobs renders idgen generator events in the Prometheus text exposition
+// format, without depending on the Prometheus client.
+//
+// It lives outside the core idgen package so that importing idgen never pulls in net/http.
+package promobs
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen"
+)
+
+// Observer is an idgen.Observer that keeps counters in memory and renders them
+// in the Prometheus text exposition format, without depending on the Prometheus client.
+//
+// Exposed metrics, all labeled with generator="<name>":
+//   - <namespace>_ids_generated_total
+//   - <namespace>_sequence_exhausted_total
+//   - <namespace>_clock_waits_total
+//   - <namespace>_clock_wait_seconds_total
+//   - <namespace>_clock_regressions_total
+//   - <namespace>_clock_regression_seconds_total
+//
+// Observer implements http.Handler, so it can be mounted directly:
+//
+//	observer := promobs.New("")
+//	generator.SetObserver(observer)
+//	http.Handle("/metrics", observer)
+type Observer struct {
+	namespace string
+
+	mu       sync.RWMutex
+	counters map[string]*generatorCounters
+}
+
+// generatorCounters holds the counters for one generator label
+type generatorCounters struct {
+	generated         atomic.Int64
+	sequenceExhausted atomic.Int64
+	waits             atomic.Int64
+	waitNanos         atomic.Int64
+	regressions       atomic.Int64
+	regressionNanos   atomic.Int64
+}
+
+var _ idgen.Observer = (*Observer)(nil)
+
+// New creates an Observer.
+// An empty namespace defaults to "idgen".
+func New(namespace string) *Observer {
+	if namespace == "" {
+		namespace = "idgen"
+	}
+	return &Observer{
+		namespace: namespace,
+		counters:  make(map[string]*generatorCounters),
+	}
+}
+
+// countersFor returns the counters for generator, creating them on first use
+func (o *Observer) countersFor(generator string) *generatorCounters {
+	o.mu.RLock()
+	c := o.counters[generator]
+	o.mu.RUnlock()
+	if c != nil {
+		return c
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if c = o.counters[generator]; c == nil {
+		c = &generatorCounters{}
+		o.counters[generator] = c
+	}
+	return c
+}
+
+// Generated implements idgen.Observer
+func (o *Observer) Generated(generator string, n int) {
+	o.countersFor(generator).generated.Add(int64(n))
+}
+
+// SequenceExhausted implements idgen.Observer
+func (o *Observer) SequenceExhausted(generator string) {
+	o.countersFor(generator).sequenceExhausted.Add(1)
+}
+
+// Waited implements idgen.Observer
+func (o *Observer) Waited(generator string, d time.Duration) {
+	c := o.countersFor(generator)
+	c.waits.Add(1)
+	c.waitNanos.Add(int64(d))
+}
+
+// ClockRegression implements idgen.Observer
+func (o *Observer) ClockRegression(generator string, delta time.Duration) {
+	c := o.countersFor(generator)
+	c.regressions.Add(1)
+	c.regressionNanos.Add(int64(delta))
+}
+
+// WriteTo writes all counters in the Prometheus text exposition format.
+// Generators are sorted by name so the output is stable.
+func (o *Observer) WriteTo(w io.Writer) (int64, error) {
+	o.mu.RLock()
+	names := make([]string, 0, len(o.counters))
+	for name := range o.counters {
+		names = append(names, name)
+	}
+	counters := make([]*generatorCounters, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		counters[i] = o.counters[name]
+	}
+	o.mu.RUnlock()
+
+	metrics := []struct {
+		name    string
+		help    string
+		seconds bool
+		value   func(c *generatorCounters) int64
+	}{
+		{"ids_generated_total", "Total number of IDs generated.", false,
+			func(c *generatorCounters) int64 { return c.generated.Load() }},
+		{"sequence_exhausted_total", "Total number of times the per-millisecond sequence was exhausted.", false,
+			func(c *generatorCounters) int64 { return c.sequenceExhausted.Load() }},
+		{"clock_waits_total", "Total number of times the generator waited for the clock.", false,
+			func(c *generatorCounters) int64 { return c.waits.Load() }},
+		{"clock_wait_seconds_total", "Total time spent waiting for the clock.", true,
+			func(c *generatorCounters) int64 { return c.waitNanos.Load() }},
+		{"clock_regressions_total", "Total number of observed clock regressions.", false,
+			func(c *generatorCounters) int64 { return c.regressions.Load() }},
+		{"clock_regression_seconds_total", "Total size of observed clock regressions.", true,
+			func(c *generatorCounters) int64 { return c.regressionNanos.Load() }},
+	}
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, m := range metrics {
+		fullName := o.namespace + "_" + m.name
+		bw.WriteString("# HELP " + fullName + " " + m.help + "\n")
+		bw.WriteString("# TYPE " + fullName + " counter\n")
+		for i, name := range names {
+			bw.WriteString(fullName + `{generator="` + name + `"} `)
+			value := m.value(counters[i])
+			if m.seconds {
+				bw.WriteString(strconv.FormatFloat(time.Duration(value).Seconds(), 'g', -1, 64))
+			} else {
+				bw.WriteString(strconv.FormatInt(value, 10))
+			}
+			bw.WriteByte('\n')
+		}
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP implements http.Handler by writing the metrics in the text exposition format.
+func (o *Observer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = o.WriteTo(w)
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
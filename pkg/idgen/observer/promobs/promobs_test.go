@@ -0,0 +1,65 @@
+package promobs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen"
+)
+
+func TestObserver(t *testing.T) {
+	observer := New("")
+	observer.Generated(idgen.GeneratorUUIDv7, 5)
+	observer.Generated(idgen.GeneratorSnowflake, 7)
+	observer.SequenceExhausted(idgen.GeneratorSnowflake)
+	observer.Waited(idgen.GeneratorSnowflake, 1500*time.Microsecond)
+	observer.ClockRegression(idgen.GeneratorSnowflake, 3*time.Millisecond)
+
+	var sb strings.Builder
+	n, err := observer.WriteTo(&sb)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if int(n) != sb.Len() {
+		t.Errorf("WriteTo() = %d bytes, wrote %d", n, sb.Len())
+	}
+
+	want := `# HELP idgen_ids_generated_total Total number of IDs generated.
+# TYPE idgen_ids_generated_total counter
+idgen_ids_generated_total{generator="snowflake"} 7
+idgen_ids_generated_total{generator="uuidv7"} 5
+# HELP idgen_sequence_exhausted_total Total number of times the per-millisecond sequence was exhausted.
+# TYPE idgen_sequence_exhausted_total counter
+idgen_sequence_exhausted_total{generator="snowflake"} 1
+idgen_sequence_exhausted_total{generator="uuidv7"} 0
+# HELP idgen_clock_waits_total Total number of times the generator waited for the clock.
+# TYPE idgen_clock_waits_total counter
+idgen_clock_waits_total{generator="snowflake"} 1
+idgen_clock_waits_total{generator="uuidv7"} 0
+# HELP idgen_clock_wait_seconds_total Total time spent waiting for the clock.
+# TYPE idgen_clock_wait_seconds_total counter
+idgen_clock_wait_seconds_total{generator="snowflake"} 0.0015
+idgen_clock_wait_seconds_total{generator="uuidv7"} 0
+# HELP idgen_clock_regressions_total Total number of observed clock regressions.
+# TYPE idgen_clock_regressions_total counter
+idgen_clock_regressions_total{generator="snowflake"} 1
+idgen_clock_regressions_total{generator="uuidv7"} 0
+# HELP idgen_clock_regression_seconds_total Total size of observed clock regressions.
+# TYPE idgen_clock_regression_seconds_total counter
+idgen_clock_regression_seconds_total{generator="snowflake"} 0.003
+idgen_clock_regression_seconds_total{generator="uuidv7"} 0
+`
+	if got := sb.String(); got != want {
+		t.Errorf("WriteTo() output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func BenchmarkSnowflakeGenerateObserved(b *testing.B) {
+	generator, _ := idgen.New(1, 2)
+	generator.SetObserver(New(""))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		generator.Generate()
+	}
+}
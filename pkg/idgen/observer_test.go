@@ -0,0 +1,218 @@
+package idgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingObserver records every event it receives
+type recordingObserver struct {
+	mu                sync.Mutex
+	generated         map[string]int
+	sequenceExhausted int
+	waits             int
+	regressions       []time.Duration
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{generated: make(map[string]int)}
+}
+
+func (r *recordingObserver) Generated(generator string, n int) {
+	r.mu.Lock()
+	r.generated[generator] += n
+	r.mu.Unlock()
+}
+
+func (r *recordingObserver) SequenceExhausted(string) {
+	r.mu.Lock()
+	r.sequenceExhausted++
+	r.mu.Unlock()
+}
+
+func (r *recordingObserver) Waited(string, time.Duration) {
+	r.mu.Lock()
+	r.waits++
+	r.mu.Unlock()
+}
+
+func (r *recordingObserver) ClockRegression(_ string, delta time.Duration) {
+	r.mu.Lock()
+	r.regressions = append(r.regressions, delta)
+	r.mu.Unlock()
+}
+
+func TestSnowflakeObserverGenerated(t *testing.T) {
+	generator, _ := New(1, 1)
+	observer := newRecordingObserver()
+	generator.SetObserver(observer)
+
+	generator.Generate()
+	generator.GenerateBatch(10)
+
+	if got := observer.generated[GeneratorSnowflake]; got != 11 {
+		t.Errorf("Generated count = %d, want 11", got)
+	}
+	if len(observer.regressions) != 0 {
+		t.Errorf("Unexpected clock regressions: %v", observer.regressions)
+	}
+}
+
+func TestSnowflakeObserverClockRegression(t *testing.T) {
+	generator, _ := New(1, 1)
+	observer := newRecordingObserver()
+	generator.SetObserver(observer)
+
+	// Simulate a clock that was 20ms ahead on the previous call
+	ahead := time.Now().UnixMilli() + 20
+	generator.lastClock = ahead
+	generator.lastTimestamp = ahead
+
+	generator.Generate()
+
+	if len(observer.regressions) == 0 {
+		t.Fatal("Expected a clock regression event")
+	}
+	if delta := observer.regressions[0]; delta <= 0 || delta > 20*time.Millisecond {
+		t.Errorf("Regression delta = %v, want (0, 20ms]", delta)
+	}
+	if observer.waits != 1 {
+		t.Errorf("Waits = %d, want 1", observer.waits)
+	}
+}
+
+func TestSnowflakeObserverReservedRangeIsNotRegression(t *testing.T) {
+	generator, _ := New(1, 1)
+	observer := newRecordingObserver()
+	generator.SetObserver(observer)
+
+	// Borrows a few milliseconds from the future
+	generator.ReserveRange(3 * (maxSequence + 1))
+	generator.Generate()
+
+	if len(observer.regressions) != 0 {
+		t.Errorf("Reserved range reported as clock regression: %v", observer.regressions)
+	}
+	if got := observer.generated[GeneratorSnowflake]; got != 3*(maxSequence+1)+1 {
+		t.Errorf("Generated count = %d, want %d", got, 3*(maxSequence+1)+1)
+	}
+}
+
+func TestAtomicSnowflakeObserverSequenceExhausted(t *testing.T) {
+	generator, _ := NewAtomic(1, 1)
+	observer := newRecordingObserver()
+	generator.SetObserver(observer)
+
+	// Exhaust the sequence of a millisecond slightly in the future
+	elapsed := time.Now().UnixMilli() - generator.Epoch() + 5
+	generator.state.Store(uint64(elapsed)<<sequenceBits | maxSequence)
+
+	generator.Generate()
+
+	if observer.sequenceExhausted == 0 {
+		t.Error("Expected a sequence exhausted event")
+	}
+	if observer.waits == 0 {
+		t.Error("Expected a wait event")
+	}
+	if got := observer.generated[GeneratorAtomicSnowflake]; got != 1 {
+		t.Errorf("Generated count = %d, want 1", got)
+	}
+}
+
+func TestUUIDv7Observer(t *testing.T) {
+	generator := &UUIDv7Generator{}
+	observer := newRecordingObserver()
+	generator.SetObserver(observer)
+
+	if _, err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := observer.generated[GeneratorUUIDv7]; got != 1 {
+		t.Errorf("Generated count = %d, want 1", got)
+	}
+
+	generator.SetObserver(nil)
+	if _, err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() with nil observer error = %v", err)
+	}
+}
+
+func TestMultiObserver(t *testing.T) {
+	a, b := newRecordingObserver(), newRecordingObserver()
+	observer := MultiObserver(a, nil, b)
+
+	observer.Generated(GeneratorSnowflake, 2)
+	observer.SequenceExhausted(GeneratorSnowflake)
+	observer.Waited(GeneratorSnowflake, time.Millisecond)
+	observer.ClockRegression(GeneratorSnowflake, time.Millisecond)
+
+	for _, r := range []*recordingObserver{a, b} {
+		if r.generated[GeneratorSnowflake] != 2 || r.sequenceExhausted != 1 || r.waits != 1 || len(r.regressions) != 1 {
+			t.Errorf("Observer did not receive all events: %+v", r)
+		}
+	}
+}
+
+func TestSnowflakeLoggerClockRegression(t *testing.T) {
+	var buf bytes.Buffer
+	generator, _ := New(3, 4)
+	generator.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	// Simulate a clock that was 10ms ahead on the previous call
+	ahead := time.Now().UnixMilli() + 10
+	generator.lastClock = ahead
+	generator.lastTimestamp = ahead
+
+	generator.Generate()
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected one JSON log record, got %q: %v", buf.String(), err)
+	}
+	if record["level"] != "WARN" || record["msg"] != "idgen: clock moved backwards" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+	if record["node"] != float64(3) || record["worker"] != float64(4) {
+		t.Errorf("Unexpected node/worker fields: %v", record)
+	}
+	if delta, ok := record["delta_ms"].(float64); !ok || delta <= 0 || delta > 10 {
+		t.Errorf("delta_ms = %v, want (0, 10]", record["delta_ms"])
+	}
+	if _, ok := record["waited_ms"].(float64); !ok {
+		t.Errorf("Missing waited_ms field: %v", record)
+	}
+}
+
+func TestSnowflakeLoggerSilentByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	generator, _ := New(3, 4)
+	generator.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	generator.SetLogger(nil)
+
+	ahead := time.Now().UnixMilli() + 5
+	generator.lastClock = ahead
+	generator.lastTimestamp = ahead
+	generator.Generate()
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no logs after SetLogger(nil), got %q", buf.String())
+	}
+}
+
+func TestUUIDv7LoggerClockRegression(t *testing.T) {
+	var buf bytes.Buffer
+	generator := &UUIDv7Generator{lastTimestamp: time.Now().UnixMilli() + 1000}
+	generator.SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	if _, err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"delta_ms"`)) {
+		t.Errorf("Expected clock regression record, got %q", buf.String())
+	}
+}
@@ -0,0 +1,74 @@
+package idgen
+
+import "sync/atomic"
+
+// Package-level generator overrides
+//
+// The Override functions replace the generators behind the package-level functions
+// for the whole process, so tests and replays can produce deterministic IDs.
+// They are meant to be used through the idtest package, which restores the
+// previous generator when the test ends.
+
+// snowflakeOverride wraps a Snowflake override so it can be stored in an atomic.Pointer
+type snowflakeOverride struct {
+	fn func() int64
+}
+
+// uuidOverride wraps a UUID override so it can be stored in an atomic.Pointer
+type uuidOverride struct {
+	fn func() (UUID, error)
+}
+
+var (
+	snowflakeOverrideFn atomic.Pointer[snowflakeOverride]
+	uuidv4OverrideFn    atomic.Pointer[uuidOverride]
+	uuidv7OverrideFn    atomic.Pointer[uuidOverride]
+)
+
+// OverrideSnowflake replaces the generator behind GenerateSnowflake and GenerateSnowflakeBatch
+// with fn until restore is called. Passing nil removes the override.
+//
+// Returns:
+//   - restore: Reinstates the override that was active before the call
+//
+// Example:
+//
+//	restore := idgen.OverrideSnowflake(func() int64 { return 42 })
+//	defer restore()
+func OverrideSnowflake(fn func() int64) (restore func()) {
+	var next *snowflakeOverride
+	if fn != nil {
+		next = &snowflakeOverride{fn: fn}
+	}
+	prev := snowflakeOverrideFn.Swap(next)
+	return func() { snowflakeOverrideFn.Store(prev) }
+}
+
+// OverrideUUIDv4 replaces the generator behind NewUUIDv4, GenerateUUIDv4 and
+// GenerateUUIDv4Batch with fn until restore is called. Passing nil removes the override.
+//
+// Returns:
+//   - restore: Reinstates the override that was active before the call
+func OverrideUUIDv4(fn func() (UUID, error)) (restore func()) {
+	return overrideUUID(&uuidv4OverrideFn, fn)
+}
+
+// OverrideUUIDv7 replaces the generator behind NewUUIDv7, NewUUIDv7Context, GenerateUUIDv7,
+// GenerateUUIDv7Batch and NewTypeID with fn until restore is called.
+// Passing nil removes the override.
+//
+// Returns:
+//   - restore: Reinstates the override that was active before the call
+func OverrideUUIDv7(fn func() (UUID, error)) (restore func()) {
+	return overrideUUID(&uuidv7OverrideFn, fn)
+}
+
+// overrideUUID swaps a UUID override and returns the function that restores the previous one
+func overrideUUID(p *atomic.Pointer[uuidOverride], fn func() (UUID, error)) func() {
+	var next *uuidOverride
+	if fn != nil {
+		next = &uuidOverride{fn: fn}
+	}
+	prev := p.Swap(next)
+	return func() { p.Store(prev) }
+}
@@ -0,0 +1,211 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Registry errors
+var (
+	// ErrGeneratorNotFound is returned when no generator is registered under a name
+	ErrGeneratorNotFound = errors.New("generator not found")
+
+	// ErrInvalidRegistration is returned when registering a nil generator or an empty name
+	ErrInvalidRegistration = errors.New("invalid generator registration")
+)
+
+// Generator is implemented by every generator in this package, so generators of
+// different kinds can be stored and used together, e.g. in a Registry.
+type Generator interface {
+	// GenerateString creates a new ID in its canonical string form:
+	// decimal for Snowflake IDs, xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx for UUIDs.
+	GenerateString(ctx context.Context) (string, error)
+}
+
+// The generators of this package implement Generator
+var (
+	_ Generator = (*Snowflake)(nil)
+	_ Generator = (*AtomicSnowflake)(nil)
+	_ Generator = (*SnowflakePool)(nil)
+	_ Generator = (*UUIDv4Generator)(nil)
+	_ Generator = (*UUIDv7Generator)(nil)
+)
+
+// GeneratorFunc adapts a function to the Generator interface
+//
+// Example:
+//
+//	orders := idgen.GeneratorFunc(func(ctx context.Context) (string, error) {
+//	    id, err := idgen.NewTypeID[OrderPrefix]()
+//	    return id.String(), err
+//	})
+type GeneratorFunc func(ctx context.Context) (string, error)
+
+// GenerateString calls f(ctx)
+func (f GeneratorFunc) GenerateString(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// Registry holds named generators, e.g. one Snowflake generator per tenant with its
+// own epoch and node range. Lookups are lock-free; registrations replace the
+// generator atomically, so a generator can be hot-swapped while it is in use.
+// The zero value is an empty registry ready to use.
+type Registry struct {
+	mu         sync.Mutex // serializes writers
+	generators atomic.Pointer[map[string]Generator]
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register stores g under name, replacing any generator already registered under that name.
+//
+// Returns:
+//   - error: ErrInvalidRegistration if name is empty or g is nil, including a nil
+//     pointer such as (*Snowflake)(nil) that would only fail when generating
+//
+// Example:
+//
+//	registry := idgen.NewRegistry()
+//	acme, _ := idgen.NewWithEpoch(0, 1, acmeEpoch)
+//	_ = registry.Register("acme", acme)
+//	id, err := registry.Generate("acme")
+func (r *Registry) Register(name string, g Generator) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidRegistration)
+	}
+	if isNil(g) {
+		return fmt.Errorf("%w: nil generator for %q", ErrInvalidRegistration, name)
+	}
+
+	r.update(func(m map[string]Generator) {
+		m[name] = g
+	})
+	return nil
+}
+
+// isNil reports whether g is nil or an interface holding a nil pointer, map,
+// slice, channel or function
+func isNil(g Generator) bool {
+	if g == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(g); v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Unregister removes the generator registered under name, if any
+func (r *Registry) Unregister(name string) {
+	r.update(func(m map[string]Generator) {
+		delete(m, name)
+	})
+}
+
+// update applies fn to a copy of the generators and publishes the copy
+func (r *Registry) update(fn func(m map[string]Generator)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var old map[string]Generator
+	if p := r.generators.Load(); p != nil {
+		old = *p
+	}
+	next := make(map[string]Generator, len(old)+1)
+	for k, v := range old {
+		next[k] = v
+	}
+	fn(next)
+	r.generators.Store(&next)
+}
+
+// Get returns the generator registered under name.
+// Use a type assertion to reach the concrete generator, e.g. g.(*idgen.Snowflake).
+//
+// Returns:
+//   - Generator: The registered generator
+//   - error: ErrGeneratorNotFound if no generator is registered under name
+func (r *Registry) Get(name string) (Generator, error) {
+	if p := r.generators.Load(); p != nil {
+		if g, ok := (*p)[name]; ok {
+			return g, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrGeneratorNotFound, name)
+}
+
+// Generate creates a new ID with the generator registered under name.
+//
+// Returns:
+//   - string: The new ID in its canonical string form
+//   - error: ErrGeneratorNotFound, or the error of the generator
+func (r *Registry) Generate(name string) (string, error) {
+	return r.GenerateContext(context.Background(), name)
+}
+
+// GenerateContext creates a new ID like Generate, passing ctx to the generator
+func (r *Registry) GenerateContext(ctx context.Context, name string) (string, error) {
+	g, err := r.Get(name)
+	if err != nil {
+		return "", err
+	}
+	return g.GenerateString(ctx)
+}
+
+// Names returns the registered names in sorted order
+func (r *Registry) Names() []string {
+	p := r.generators.Load()
+	if p == nil {
+		return nil
+	}
+	names := make([]string, 0, len(*p))
+	for name := range *p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultRegistry backs the package-level registry functions
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the registry used by the package-level Register, Unregister,
+// Lookup, Generate and GenerateContext functions
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Register stores g under name in the default registry. See Registry.Register.
+func Register(name string, g Generator) error {
+	return defaultRegistry.Register(name, g)
+}
+
+// Unregister removes the generator registered under name from the default registry
+func Unregister(name string) {
+	defaultRegistry.Unregister(name)
+}
+
+// Lookup returns the generator registered under name in the default registry. See Registry.Get.
+func Lookup(name string) (Generator, error) {
+	return defaultRegistry.Get(name)
+}
+
+// Generate creates a new ID with the generator registered under name in the default registry.
+// See Registry.Generate.
+func Generate(name string) (string, error) {
+	return defaultRegistry.Generate(name)
+}
+
+// GenerateContext creates a new ID like Generate, passing ctx to the generator
+func GenerateContext(ctx context.Context, name string) (string, error) {
+	return defaultRegistry.GenerateContext(ctx, name)
+}
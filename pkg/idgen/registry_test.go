@@ -0,0 +1,161 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	acme, _ := NewWithEpoch(1, 2, DefaultEpoch-1000)
+	globex, _ := NewAtomicWithEpoch(3, 4, DefaultEpoch)
+	pool, _ := NewSnowflakePool(5, 4, PoolRoundRobin)
+	uuids := &UUIDv7Generator{}
+
+	for name, g := range map[string]Generator{
+		"acme":   acme,
+		"globex": globex,
+		"pool":   pool,
+		"uuid":   uuids,
+		"v4":     NewUUIDv4Generator(),
+		"static": GeneratorFunc(func(context.Context) (string, error) { return "fixed", nil }),
+	} {
+		if err := registry.Register(name, g); err != nil {
+			t.Fatalf("Register(%q) error = %v", name, err)
+		}
+	}
+
+	if got, want := registry.Names(), []string{"acme", "globex", "pool", "static", "uuid", "v4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+
+	s, err := registry.Generate("acme")
+	if err != nil {
+		t.Fatalf("Generate(acme) error = %v", err)
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		t.Fatalf("Generate(acme) = %q is not decimal", s)
+	}
+	if acme.ExtractProcessID(id) != 1 || acme.ExtractWorkerID(id) != 2 {
+		t.Errorf("Generate(acme) = %d was not generated by acme", id)
+	}
+
+	for _, name := range []string{"globex", "pool"} {
+		if s, err := registry.Generate(name); err != nil || len(s) < 10 {
+			t.Errorf("Generate(%q) = %q, %v", name, s, err)
+		}
+	}
+	for _, name := range []string{"uuid", "v4"} {
+		s, err := registry.Generate(name)
+		if err != nil {
+			t.Fatalf("Generate(%q) error = %v", name, err)
+		}
+		if _, err := ParseUUID(s); err != nil {
+			t.Errorf("Generate(%q) = %q is not a UUID: %v", name, s, err)
+		}
+	}
+	if s, _ := registry.Generate("static"); s != "fixed" {
+		t.Errorf("Generate(static) = %q, want %q", s, "fixed")
+	}
+
+	g, err := registry.Get("acme")
+	if err != nil || g.(*Snowflake) != acme {
+		t.Errorf("Get(acme) = %v, %v", g, err)
+	}
+
+	registry.Unregister("acme")
+	if _, err := registry.Generate("acme"); !errors.Is(err, ErrGeneratorNotFound) {
+		t.Errorf("Expected ErrGeneratorNotFound after Unregister, got %v", err)
+	}
+}
+
+func TestRegistryErrors(t *testing.T) {
+	var registry Registry
+
+	if _, err := registry.Get("missing"); !errors.Is(err, ErrGeneratorNotFound) {
+		t.Errorf("Expected ErrGeneratorNotFound, got %v", err)
+	}
+	if names := registry.Names(); len(names) != 0 {
+		t.Errorf("Names() = %v, want none", names)
+	}
+	if err := registry.Register("", NewUUIDv4Generator()); !errors.Is(err, ErrInvalidRegistration) {
+		t.Errorf("Expected ErrInvalidRegistration for empty name, got %v", err)
+	}
+	if err := registry.Register("nil", nil); !errors.Is(err, ErrInvalidRegistration) {
+		t.Errorf("Expected ErrInvalidRegistration for nil generator, got %v", err)
+	}
+	if err := registry.Register("typed nil", (*Snowflake)(nil)); !errors.Is(err, ErrInvalidRegistration) {
+		t.Errorf("Expected ErrInvalidRegistration for typed nil generator, got %v", err)
+	}
+	if _, err := registry.Get("typed nil"); !errors.Is(err, ErrGeneratorNotFound) {
+		t.Errorf("Expected typed nil generator not to be registered, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = registry.Register("v4", NewUUIDv4Generator())
+	if _, err := registry.GenerateContext(ctx, "v4"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRegistryHotSwap(t *testing.T) {
+	registry := NewRegistry()
+	first := GeneratorFunc(func(context.Context) (string, error) { return "first", nil })
+	second := GeneratorFunc(func(context.Context) (string, error) { return "second", nil })
+	_ = registry.Register("tenant", first)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				s, err := registry.Generate("tenant")
+				if err != nil || (s != "first" && s != "second") {
+					t.Errorf("Generate() = %q, %v", s, err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		_ = registry.Register("tenant", second)
+		_ = registry.Register(fmt.Sprintf("other-%d", i), first)
+		_ = registry.Register("tenant", first)
+	}
+	wg.Wait()
+
+	_ = registry.Register("tenant", second)
+	if s, _ := registry.Generate("tenant"); s != "second" {
+		t.Errorf("Generate() after swap = %q, want %q", s, "second")
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	t.Cleanup(func() { Unregister("orders") })
+
+	generator, _ := New(7, 7)
+	if err := Register("orders", generator); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if g, err := Lookup("orders"); err != nil || g != Generator(generator) {
+		t.Errorf("Lookup() = %v, %v", g, err)
+	}
+	if _, err := DefaultRegistry().Get("orders"); err != nil {
+		t.Errorf("DefaultRegistry().Get() error = %v", err)
+	}
+	if _, err := Generate("orders"); err != nil {
+		t.Errorf("Generate() error = %v", err)
+	}
+	if _, err := GenerateContext(context.Background(), "missing"); !errors.Is(err, ErrGeneratorNotFound) {
+		t.Errorf("Expected ErrGeneratorNotFound, got %v", err)
+	}
+}
@@ -1,7 +1,11 @@
 package idgen
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -22,6 +26,9 @@ import (
 // Sequence (12 bits): Incremental sequence per millisecond (0-4095 IDs/ms)
 //
 // Total capacity: 32 processes × 32 workers × 4096 IDs/ms = ~4.1M IDs per millisecond
+//
+// This is DefaultLayout. Other layouts, such as DiscordLayout or InstagramLayout,
+// can be used with NewWithLayout.
 
 const (
 	// Epoch is the custom epoch (January 1, 2025 00:00:00 UTC)
@@ -74,16 +81,57 @@ var (
 
 	// ErrClockMovedBackwards is returned when system clock moves backwards
 	ErrClockMovedBackwards = errors.New("clock moved backwards")
+
+	// ErrEpochExhausted is returned when the time since the epoch no longer fits
+	// in the timestamp field of the layout
+	ErrEpochExhausted = errors.New("epoch exhausted: timestamp does not fit in the layout")
+
+	// ErrClockBeforeEpoch is returned when the clock reads a time before the generator epoch,
+	// which would make the timestamp field negative
+	ErrClockBeforeEpoch = errors.New("clock is before the epoch")
+)
+
+// ClockPolicy controls how a Snowflake generator reacts when the system clock moves backwards
+type ClockPolicy int
+
+const (
+	// ClockWait waits until the clock catches up with the last issued timestamp. This is the default.
+	ClockWait ClockPolicy = iota
+
+	// ClockFail makes the error-returning methods (GenerateContext, GenerateString,
+	// GenerateBatchE and ReserveRangeContext) return ErrClockMovedBackwards immediately
+	// instead of waiting, so callers can fail over to another generator. The panicking
+	// methods (Generate, GenerateBatch and ReserveRange) ignore it.
+	ClockFail
 )
 
+// String returns the policy name used in configuration: "wait" or "fail"
+func (p ClockPolicy) String() string {
+	switch p {
+	case ClockWait:
+		return "wait"
+	case ClockFail:
+		return "fail"
+	}
+	return "ClockPolicy(" + strconv.Itoa(int(p)) + ")"
+}
+
 // Snowflake generates unique 64-bit IDs in a distributed system
 type Snowflake struct {
-	mu            sync.Mutex
-	epoch         int64
-	processID     int64
-	workerID      int64
-	sequence      int64
-	lastTimestamp int64
+	instrumented
+	mu             sync.Mutex
+	layout         Layout
+	epoch          int64
+	processID      int64
+	workerID       int64
+	node           int64 // pre-shifted processID and workerID bits
+	sequence       int64
+	lastTimestamp  int64
+	lastClock      int64 // latest wall clock reading, to tell clock regressions from reserved ranges
+	clockPolicy    ClockPolicy
+	clock          Clock      // nil means time.Now
+	store          StateStore // nil disables persistence
+	persistedUntil int64      // timestamp horizon saved to store
 }
 
 // New creates a new Snowflake ID generator.
@@ -118,27 +166,43 @@ func New(processID, workerID int64) (*Snowflake, error) {
 //
 // Returns:
 //   - *Snowflake: A new ID generator instance
-//   - error: ErrInvalidProcessID or ErrInvalidWorkerID if parameters are out of range
+//   - error: ErrInvalidProcessID or ErrInvalidWorkerID if parameters are out of range,
+//     or ErrClockBeforeEpoch if epoch is in the future
 //
 // Example:
 //
 //	customEpoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
 //	generator, err := idgen.NewWithEpoch(5, 12, customEpoch)
 func NewWithEpoch(processID, workerID int64, epoch int64) (*Snowflake, error) {
-	if processID < 0 || processID > maxProcessID {
-		return nil, ErrInvalidProcessID
-	}
-	if workerID < 0 || workerID > maxWorkerID {
-		return nil, ErrInvalidWorkerID
-	}
+	return NewWithLayout(processID, workerID, DefaultLayout, epoch)
+}
 
-	return &Snowflake{
-		epoch:         epoch,
-		processID:     processID,
-		workerID:      workerID,
-		sequence:      0,
-		lastTimestamp: 0,
-	}, nil
+// NewWithLayout creates a new Snowflake ID generator with a custom bit layout and epoch.
+// This is useful to generate IDs compatible with another Snowflake scheme.
+//
+// Parameters:
+//   - processID: Unique process identifier (0 to layout.MaxProcessID())
+//   - workerID: Unique worker identifier within the process (0 to layout.MaxWorkerID())
+//   - layout: Bit layout of the generated IDs
+//   - epoch: Custom epoch in milliseconds since Unix epoch
+//
+// Returns:
+//   - *Snowflake: A new ID generator instance
+//   - error: ErrInvalidLayout, ErrInvalidProcessID or ErrInvalidWorkerID if parameters are invalid,
+//     or ErrClockBeforeEpoch if epoch is in the future
+//
+// Example:
+//
+//	// Instagram-style IDs for logical shard 1341
+//	generator, err := idgen.NewWithLayout(1341, 0, idgen.InstagramLayout, idgen.InstagramEpoch)
+func NewWithLayout(processID, workerID int64, layout Layout, epoch int64) (*Snowflake, error) {
+	s, err := NewSnowflakeGenerator(WithNode(processID, workerID), WithLayout(layout), WithEpoch(epoch))
+	var optErr *OptionError
+	if errors.As(err, &optErr) {
+		// Return the bare sentinel errors, as before NewSnowflakeGenerator existed
+		return nil, optErr.Err
+	}
+	return s, err
 }
 
 // NewSnowflake creates a new Snowflake ID generator.
@@ -161,6 +225,13 @@ func NewSnowflakeWithEpoch(processID, workerID int64, epoch int64) (*Snowflake,
 //   - Approximately sortable by creation time
 //   - Positive (fits in int64 without issues)
 //
+// Generate panics with ErrEpochExhausted once the layout lifetime is over, and with
+// ErrClockBeforeEpoch while the clock is before the epoch, instead of overflowing
+// into the sign bit. With WithStateStore, it also panics with an error wrapping
+// ErrStateStore when the store fails. Use GenerateContext to handle these as errors.
+// Generate always waits out a clock regression, even with ClockFail; only the
+// error-returning methods such as GenerateContext report ErrClockMovedBackwards.
+//
 // Returns:
 //   - int64: A unique 64-bit Snowflake ID
 //
@@ -170,48 +241,179 @@ func NewSnowflakeWithEpoch(processID, workerID int64, epoch int64) (*Snowflake,
 //	id := generator.Generate()
 //	fmt.Printf("Generated ID: %d\n", id)
 func (s *Snowflake) Generate() int64 {
+	// The background context is never canceled and clock regressions are waited out,
+	// so the only possible errors are ErrEpochExhausted, ErrClockBeforeEpoch and,
+	// with a state store, ErrStateStore
+	id, err := s.generate(context.Background(), false)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// GenerateID creates a new unique Snowflake ID like Generate, returned as a SnowflakeID.
+//
+// Example:
+//
+//	generator, _ := idgen.New(5, 12)
+//	id := generator.GenerateID()
+//	payload, _ := json.Marshal(map[string]any{"id": id}) // {"id":"..."}
+func (s *Snowflake) GenerateID() SnowflakeID {
+	return SnowflakeID(s.Generate())
+}
+
+// GenerateContext creates a new unique Snowflake ID like Generate, but stops waiting
+// for the clock when ctx is canceled or its deadline would expire.
+//
+// The generator waits when the sequence of the current millisecond is exhausted
+// or when the clock moved backwards. If ctx ends first, no ID is issued and a
+// *WaitCanceledError wrapping ctx.Err() is returned.
+//
+// Returns:
+//   - int64: A unique 64-bit Snowflake ID
+//   - error: *WaitCanceledError if ctx ended while waiting, ErrEpochExhausted, ErrClockBeforeEpoch,
+//     ErrClockMovedBackwards with ClockFail, or ErrStateStore if the state store fails
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+//	defer cancel()
+//	id, err := generator.GenerateContext(ctx)
+func (s *Snowflake) GenerateContext(ctx context.Context) (int64, error) {
+	return s.generate(ctx, true)
+}
+
+// generate issues the next ID. A clock regression fails with ErrClockMovedBackwards
+// only when applyPolicy is set and the clock policy is ClockFail.
+func (s *Snowflake) generate(ctx context.Context, applyPolicy bool) (int64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	timestamp := s.currentTimestamp()
+	h := s.loadHooks()
+	id, err := s.next(ctx, h, applyPolicy)
+	if err != nil {
+		return 0, err
+	}
+	h.observer.Generated(GeneratorSnowflake, 1)
+	return id, nil
+}
 
-	// Clock moved backwards - wait until it catches up
+// fill issues len(dst) IDs in one critical section. Like generate, it waits for
+// the clock instead of claiming future milliseconds.
+func (s *Snowflake) fill(ctx context.Context, dst []int64, applyPolicy bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.loadHooks()
+	for i := range dst {
+		id, err := s.next(ctx, h, applyPolicy)
+		if err != nil {
+			if i > 0 {
+				h.observer.Generated(GeneratorSnowflake, i)
+			}
+			return err
+		}
+		dst[i] = id
+	}
+	if len(dst) > 0 {
+		h.observer.Generated(GeneratorSnowflake, len(dst))
+	}
+	return nil
+}
+
+// next advances the generator state and returns the next ID. Must be called with s.mu held.
+func (s *Snowflake) next(ctx context.Context, h *hooks, applyPolicy bool) (int64, error) {
+	timestamp, regression := s.readClock(h)
+	if timestamp < s.epoch {
+		return 0, s.beforeEpoch(ctx, h, timestamp)
+	}
+
+	// Clock moved backwards (or a reserved range ends in the future) - wait until it catches up
 	if timestamp < s.lastTimestamp {
-		// In production, you might want to return an error here
-		// For now, we'll wait
-		time.Sleep(time.Duration(s.lastTimestamp-timestamp) * time.Millisecond)
-		timestamp = s.currentTimestamp()
+		if regression > 0 && applyPolicy && s.clockPolicy == ClockFail {
+			s.logClockRegression(ctx, h, regression, 0)
+			return 0, fmt.Errorf("%w by %v", ErrClockMovedBackwards, regression)
+		}
+
+		var waited time.Duration
+		for timestamp < s.lastTimestamp {
+			wait := time.Duration(s.lastTimestamp-timestamp) * time.Millisecond
+			if err := sleepContext(ctx, wait); err != nil {
+				return 0, &WaitCanceledError{Reason: reasonClockBackwards, Err: err}
+			}
+			waited += wait
+			timestamp, _ = s.readClock(h)
+		}
+		h.observer.Waited(GeneratorSnowflake, waited)
+		if regression > 0 {
+			s.logClockRegression(ctx, h, regression, waited)
+		}
 	}
 
+	sequence := int64(0)
+
 	// Same millisecond - increment sequence
 	if timestamp == s.lastTimestamp {
-		s.sequence = (s.sequence + 1) & maxSequence
+		sequence = (s.sequence + 1) & s.layout.MaxSequence()
 
 		// Sequence overflow - wait for next millisecond
-		if s.sequence == 0 {
-			timestamp = s.waitNextMillis(s.lastTimestamp)
+		if sequence == 0 {
+			h.observer.SequenceExhausted(GeneratorSnowflake)
+			start := time.Now()
+			var err error
+			timestamp, err = s.waitNextMillis(ctx, s.lastTimestamp)
+			if err != nil {
+				return 0, &WaitCanceledError{Reason: reasonSequenceExhausted, Err: err}
+			}
+			waited := time.Since(start)
+			h.observer.Waited(GeneratorSnowflake, waited)
+			h.log(ctx, slog.LevelDebug, "idgen: sequence exhausted",
+				slog.String("generator", GeneratorSnowflake),
+				slog.Int64("node", s.processID),
+				slog.Int64("worker", s.workerID),
+				slog.Int64("waited_ms", waited.Milliseconds()),
+			)
 		}
-	} else {
-		// New millisecond - reset sequence
-		s.sequence = 0
 	}
 
+	if timestamp-s.epoch > s.layout.timestampLimit() {
+		s.logEpochExhausted(ctx, h)
+		return 0, ErrEpochExhausted
+	}
+	if err := s.persist(ctx, timestamp); err != nil {
+		return 0, err
+	}
+
+	s.sequence = sequence
 	s.lastTimestamp = timestamp
 
 	// Construct the ID (Discord/Twitter Snowflake format)
 	// [1 bit sign (0)] [41 bits timestamp] [5 bits processID] [5 bits workerID] [12 bits sequence]
-	id := ((timestamp - s.epoch) << timestampShift) |
-		(s.processID << processIDShift) |
-		(s.workerID << workerIDShift) |
-		s.sequence
+	return s.compose(timestamp, s.sequence), nil
+}
 
-	return id
+// GenerateString creates a new Snowflake ID in decimal form, implementing Generator.
+//
+// Returns:
+//   - string: The decimal ID
+//   - error: The errors of GenerateContext
+func (s *Snowflake) GenerateString(ctx context.Context) (string, error) {
+	id, err := s.GenerateContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(id, 10), nil
 }
 
 // GenerateBatch generates multiple IDs at once for better performance.
 // This method is more efficient than calling Generate() multiple times
 // when you need many IDs at once.
 //
+// The IDs are never stamped ahead of the clock: when the sequence of the current
+// millisecond runs out, the generator waits for the next one, so a batch of n IDs
+// takes about n/4096 ms with DefaultLayout. Use ReserveRange to claim future
+// milliseconds instead of waiting.
+//
 // Parameters:
 //   - count: Number of IDs to generate
 //
@@ -225,12 +427,172 @@ func (s *Snowflake) Generate() int64 {
 //	fmt.Printf("Generated %d IDs\n", len(ids))
 func (s *Snowflake) GenerateBatch(count int) []int64 {
 	ids := make([]int64, count)
-	for i := 0; i < count; i++ {
-		ids[i] = s.Generate()
-	}
+	s.GenerateBatchInto(ids)
 	return ids
 }
 
+// GenerateBatchInto fills dst with unique, strictly increasing Snowflake IDs.
+// The generator lock is taken once and no memory is allocated. Like GenerateBatch,
+// it waits for the clock rather than stamping IDs ahead of it.
+//
+// Parameters:
+//   - dst: Slice to fill; every element is overwritten
+//
+// Example:
+//
+//	generator, _ := idgen.New(5, 12)
+//	buf := make([]int64, 1000)
+//	generator.GenerateBatchInto(buf)
+func (s *Snowflake) GenerateBatchInto(dst []int64) {
+	// Clock regressions are waited out, so errors are the ones Generate panics with
+	if err := s.fill(context.Background(), dst, false); err != nil {
+		panic(err)
+	}
+}
+
+// GenerateBatchE generates count IDs like GenerateBatch, but returns an error
+// instead of panicking.
+//
+// Parameters:
+//   - count: Number of IDs to generate
+//
+// Returns:
+//   - []int64: Slice of unique Snowflake IDs, nil on error
+//   - error: ErrEpochExhausted, ErrClockBeforeEpoch, ErrClockMovedBackwards with ClockFail,
+//     ErrStateStore if the state store fails, or an error for a negative count
+//
+// Example:
+//
+//	ids, err := generator.GenerateBatchE(100)
+//	if err != nil {
+//	    return err
+//	}
+func (s *Snowflake) GenerateBatchE(count int) ([]int64, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %d", count)
+	}
+	ids := make([]int64, count)
+	if err := s.fill(context.Background(), ids, true); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ReserveRange atomically claims count consecutive IDs in one critical section
+// and returns them as a compact IDRange.
+//
+// The range starts right after the last ID issued by the generator and spans
+// as many milliseconds as needed, 4096 IDs per millisecond with DefaultLayout. When the range
+// extends past the current millisecond, future milliseconds are claimed
+// instead of sleeping, so ReserveRange never blocks on sequence exhaustion.
+// Subsequent calls to Generate wait for the clock to catch up with the end
+// of the range, exactly as if the clock had moved backwards.
+//
+// ReserveRange panics with ErrEpochExhausted if the range would extend past
+// the layout lifetime, or with an error wrapping ErrStateStore if the state
+// store fails; no IDs are reserved in either case. Use ReserveRangeContext
+// to handle these as errors.
+//
+// Parameters:
+//   - count: Number of IDs to reserve; count <= 0 returns an empty range
+//
+// Returns:
+//   - IDRange: The reserved IDs
+//
+// Example:
+//
+//	generator, _ := idgen.New(5, 12)
+//	r := generator.ReserveRange(10000)
+//	for id, ok := r.Next(); ok; id, ok = r.Next() {
+//	    fmt.Println(id)
+//	}
+func (s *Snowflake) ReserveRange(count int) IDRange {
+	r, err := s.reserve(context.Background(), count, false)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// ReserveRangeContext claims count consecutive IDs like ReserveRange,
+// but returns an error instead of panicking. ctx is passed to the state store and the logger.
+//
+// Parameters:
+//   - ctx: Context for the state store and the logger
+//   - count: Number of IDs to reserve; count <= 0 returns an empty range
+//
+// Returns:
+//   - IDRange: The reserved IDs, empty on error
+//   - error: ErrEpochExhausted, ErrClockBeforeEpoch, ErrClockMovedBackwards with ClockFail,
+//     or ErrStateStore if the state store fails
+//
+// Example:
+//
+//	r, err := generator.ReserveRangeContext(ctx, 10000)
+//	if err != nil {
+//	    return err
+//	}
+func (s *Snowflake) ReserveRangeContext(ctx context.Context, count int) (IDRange, error) {
+	return s.reserve(ctx, count, true)
+}
+
+// reserve claims count consecutive IDs. A clock regression fails with ErrClockMovedBackwards
+// only when applyPolicy is set and the clock policy is ClockFail.
+func (s *Snowflake) reserve(ctx context.Context, count int, applyPolicy bool) (IDRange, error) {
+	if count <= 0 {
+		return IDRange{}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.loadHooks()
+	timestamp, regression := s.readClock(h)
+	if timestamp < s.epoch {
+		return IDRange{}, s.beforeEpoch(ctx, h, timestamp)
+	}
+	if regression > 0 {
+		s.logClockRegression(ctx, h, regression, 0)
+		if applyPolicy && s.clockPolicy == ClockFail {
+			return IDRange{}, fmt.Errorf("%w by %v", ErrClockMovedBackwards, regression)
+		}
+	}
+	sequence := int64(0)
+
+	// Continue from the last issued ID when the clock has not moved past it
+	if timestamp <= s.lastTimestamp {
+		timestamp = s.lastTimestamp
+		sequence = s.sequence + 1
+		if sequence > s.layout.MaxSequence() {
+			timestamp++
+			sequence = 0
+		}
+	}
+
+	start := s.compose(timestamp, sequence)
+
+	// Advance the generator state to the last ID of the range
+	last := sequence + int64(count) - 1
+	end := timestamp + last>>s.layout.SequenceBits
+	if end-s.epoch > s.layout.timestampLimit() {
+		s.logEpochExhausted(ctx, h)
+		return IDRange{}, ErrEpochExhausted
+	}
+	if err := s.persist(ctx, end); err != nil {
+		return IDRange{}, err
+	}
+	s.lastTimestamp = end
+	s.sequence = last & s.layout.MaxSequence()
+
+	h.observer.Generated(GeneratorSnowflake, count)
+	return IDRange{Start: start, Count: count, layout: s.layout}, nil
+}
+
+// compose builds an ID from a Unix millisecond timestamp and a sequence number
+func (s *Snowflake) compose(timestamp, sequence int64) int64 {
+	return ((timestamp - s.epoch) << s.layout.timestampShift()) | s.node | sequence
+}
+
 // ExtractTimestamp extracts the timestamp component from a Snowflake ID.
 // Returns the timestamp in milliseconds since Unix epoch.
 //
@@ -247,7 +609,7 @@ func (s *Snowflake) GenerateBatch(count int) []int64 {
 //	timestamp := generator.ExtractTimestamp(id)
 //	fmt.Printf("ID was created at: %d ms\n", timestamp)
 func (s *Snowflake) ExtractTimestamp(id int64) int64 {
-	return (id >> timestampShift) + s.epoch
+	return decodeSnowflake(id, s.layout, s.epoch).Timestamp
 }
 
 // ExtractProcessID extracts the process ID component from a Snowflake ID.
@@ -256,9 +618,9 @@ func (s *Snowflake) ExtractTimestamp(id int64) int64 {
 //   - id: A Snowflake ID to extract process ID from
 //
 // Returns:
-//   - int64: Process ID (0-31)
+//   - int64: Process ID (0-31 with DefaultLayout)
 func (s *Snowflake) ExtractProcessID(id int64) int64 {
-	return (id >> processIDShift) & maxProcessID
+	return decodeSnowflake(id, s.layout, s.epoch).ProcessID
 }
 
 // ExtractWorkerID extracts the worker ID component from a Snowflake ID.
@@ -267,9 +629,9 @@ func (s *Snowflake) ExtractProcessID(id int64) int64 {
 //   - id: A Snowflake ID to extract worker ID from
 //
 // Returns:
-//   - int64: Worker ID (0-31)
+//   - int64: Worker ID (0-31 with DefaultLayout)
 func (s *Snowflake) ExtractWorkerID(id int64) int64 {
-	return (id >> workerIDShift) & maxWorkerID
+	return decodeSnowflake(id, s.layout, s.epoch).WorkerID
 }
 
 // ExtractSequence extracts the sequence number from a Snowflake ID.
@@ -279,9 +641,9 @@ func (s *Snowflake) ExtractWorkerID(id int64) int64 {
 //   - id: A Snowflake ID to extract sequence from
 //
 // Returns:
-//   - int64: Sequence number (0-4095)
+//   - int64: Sequence number (0-4095 with DefaultLayout)
 func (s *Snowflake) ExtractSequence(id int64) int64 {
-	return id & maxSequence
+	return decodeSnowflake(id, s.layout, s.epoch).Sequence
 }
 
 // ExtractTime converts the Snowflake ID timestamp to a time.Time object.
@@ -306,17 +668,113 @@ func (s *Snowflake) ExtractTime(id int64) time.Time {
 
 // currentTimestamp returns current timestamp in milliseconds
 func (s *Snowflake) currentTimestamp() int64 {
-	return time.Now().UnixMilli()
+	if s.clock == nil {
+		return time.Now().UnixMilli()
+	}
+	return s.clock.Now().UnixMilli()
+}
+
+// untilNextMillis returns how long to wait until the clock reaches the millisecond after last
+func (s *Snowflake) untilNextMillis(last int64) time.Duration {
+	if s.clock == nil {
+		return untilNextMillis(last)
+	}
+	return time.UnixMilli(last + 1).Sub(s.clock.Now())
+}
+
+// readClock returns the current timestamp and, when the clock moved backwards,
+// how far it regressed. Must be called with s.mu held.
+func (s *Snowflake) readClock(h *hooks) (timestamp int64, regression time.Duration) {
+	now := s.currentTimestamp()
+	if now < s.lastClock {
+		regression = time.Duration(s.lastClock-now) * time.Millisecond
+		h.observer.ClockRegression(GeneratorSnowflake, regression)
+	} else {
+		s.lastClock = now
+	}
+	return now, regression
+}
+
+// logClockRegression logs a clock regression and how long the generator waited for it
+func (s *Snowflake) logClockRegression(ctx context.Context, h *hooks, regression, waited time.Duration) {
+	h.log(ctx, slog.LevelWarn, "idgen: clock moved backwards",
+		slog.String("generator", GeneratorSnowflake),
+		slog.Int64("node", s.processID),
+		slog.Int64("worker", s.workerID),
+		slog.Int64("delta_ms", regression.Milliseconds()),
+		slog.Int64("waited_ms", waited.Milliseconds()),
+	)
 }
 
-// waitNextMillis waits until next millisecond
-func (s *Snowflake) waitNextMillis(lastTimestamp int64) int64 {
+// beforeEpoch logs and returns the error for a clock reading before the epoch
+func (s *Snowflake) beforeEpoch(ctx context.Context, h *hooks, timestamp int64) error {
+	h.log(ctx, slog.LevelError, "idgen: clock before epoch",
+		slog.String("generator", GeneratorSnowflake),
+		slog.Int64("node", s.processID),
+		slog.Int64("worker", s.workerID),
+		slog.Int64("delta_ms", s.epoch-timestamp),
+	)
+	return fmt.Errorf("%w by %v", ErrClockBeforeEpoch, time.Duration(s.epoch-timestamp)*time.Millisecond)
+}
+
+// logEpochExhausted logs that the layout lifetime is over
+func (s *Snowflake) logEpochExhausted(ctx context.Context, h *hooks) {
+	h.log(ctx, slog.LevelError, "idgen: epoch exhausted",
+		slog.String("generator", GeneratorSnowflake),
+		slog.Int64("node", s.processID),
+		slog.Int64("worker", s.workerID),
+	)
+}
+
+// SetClockPolicy sets how the generator reacts when the system clock moves backwards.
+// The default is ClockWait. It is safe to call while the generator is in use.
+func (s *Snowflake) SetClockPolicy(p ClockPolicy) {
+	s.mu.Lock()
+	s.clockPolicy = p
+	s.mu.Unlock()
+}
+
+// ClockPolicy returns the clock policy of the generator
+func (s *Snowflake) ClockPolicy() ClockPolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clockPolicy
+}
+
+// SetObserver attaches an Observer that receives generation, wait and clock events.
+// Passing nil restores the default no-op observer.
+// It is safe to call while the generator is in use.
+//
+// Example:
+//
+//	generator.SetObserver(expvarobs.New("idgen"))
+func (s *Snowflake) SetObserver(o Observer) {
+	s.setObserver(o)
+}
+
+// SetLogger attaches a structured logger that records clock anomalies,
+// such as clock regressions and sequence exhaustion, with the fields
+// node, worker, delta_ms and waited_ms.
+// Passing nil disables logging, which is the default.
+// It is safe to call while the generator is in use.
+//
+// Example:
+//
+//	generator.SetLogger(slog.Default())
+func (s *Snowflake) SetLogger(l *slog.Logger) {
+	s.setLogger(l)
+}
+
+// waitNextMillis waits until next millisecond or until ctx is done
+func (s *Snowflake) waitNextMillis(ctx context.Context, lastTimestamp int64) (int64, error) {
 	timestamp := s.currentTimestamp()
 	for timestamp <= lastTimestamp {
-		time.Sleep(100 * time.Microsecond)
+		if err := sleepContext(ctx, s.untilNextMillis(lastTimestamp)); err != nil {
+			return 0, err
+		}
 		timestamp = s.currentTimestamp()
 	}
-	return timestamp
+	return timestamp, nil
 }
 
 // ProcessID returns the process ID configured for this generator.
@@ -342,3 +800,33 @@ func (s *Snowflake) WorkerID() int64 {
 func (s *Snowflake) Epoch() int64 {
 	return s.epoch
 }
+
+// Layout returns the bit layout configured for this generator.
+//
+// Returns:
+//   - Layout: DefaultLayout unless the generator was created with NewWithLayout
+func (s *Snowflake) Layout() Layout {
+	return s.layout
+}
+
+// RemainingLifetime returns how long the generator can keep producing IDs
+// before it returns ErrEpochExhausted. It returns 0 once the lifetime is over
+// and is capped at the largest time.Duration.
+//
+// Example:
+//
+//	if generator.RemainingLifetime() < 365*24*time.Hour {
+//	    log.Println("Snowflake epoch expires within a year")
+//	}
+func (s *Snowflake) RemainingLifetime() time.Duration {
+	return remainingLifetime(s.layout, s.epoch)
+}
+
+// remainingLifetime returns the time left until the layout is exhausted
+func remainingLifetime(layout Layout, epoch int64) time.Duration {
+	end := time.UnixMilli(epoch + layout.timestampLimit() + 1)
+	if remaining := time.Until(end); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
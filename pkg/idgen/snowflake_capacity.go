@@ -0,0 +1,82 @@
+package idgen
+
+import (
+	"math"
+	"time"
+)
+
+// Capacity describes the lifetime and throughput limits of a Snowflake layout
+// combined with an epoch.
+type Capacity struct {
+	// Epoch is the first instant the layout can represent
+	Epoch time.Time
+
+	// ExhaustedAt is the first instant the timestamp field can no longer represent.
+	// Generators return ErrEpochExhausted from then on.
+	ExhaustedAt time.Time
+
+	// Lifetime is the time between Epoch and ExhaustedAt,
+	// capped at the largest time.Duration (about 292 years)
+	Lifetime time.Duration
+
+	// Nodes is the number of distinct process and worker ID combinations
+	Nodes int64
+
+	// IDsPerSecondPerWorker is the peak throughput of a single generator
+	IDsPerSecondPerWorker int64
+
+	// IDsPerSecond is the peak throughput of all nodes together,
+	// capped at math.MaxInt64
+	IDsPerSecond int64
+}
+
+// Capacity reports how long the layout lasts from epoch and how many IDs it can produce.
+//
+// Parameters:
+//   - epoch: The generator epoch in milliseconds since Unix epoch
+//
+// Returns:
+//   - Capacity: Lifetime and throughput of the layout
+//   - error: ErrInvalidLayout if the layout is invalid
+//
+// Example:
+//
+//	c, _ := idgen.DefaultLayout.Capacity(idgen.DefaultEpoch)
+//	fmt.Println(c.ExhaustedAt)           // 2094-09-07 15:47:35.551 +0000 UTC
+//	fmt.Println(c.IDsPerSecondPerWorker) // 4096000
+func (l Layout) Capacity(epoch int64) (Capacity, error) {
+	if err := l.Validate(); err != nil {
+		return Capacity{}, err
+	}
+
+	start := time.UnixMilli(epoch).UTC()
+	end := time.UnixMilli(epoch + l.timestampLimit() + 1).UTC()
+	nodes := (l.MaxProcessID() + 1) * (l.MaxWorkerID() + 1)
+	perWorker := saturatingMul(l.MaxSequence()+1, 1000)
+
+	return Capacity{
+		Epoch:                 start,
+		ExhaustedAt:           end,
+		Lifetime:              end.Sub(start),
+		Nodes:                 nodes,
+		IDsPerSecondPerWorker: perWorker,
+		IDsPerSecond:          saturatingMul(nodes, perWorker),
+	}, nil
+}
+
+// timestampLimit returns the largest timestamp field value that keeps IDs positive
+func (l Layout) timestampLimit() int64 {
+	limit := fieldMask(l.TimestampBits)
+	if positive := uint64(math.MaxInt64) >> l.timestampShift(); positive < limit {
+		limit = positive
+	}
+	return int64(limit)
+}
+
+// saturatingMul multiplies two non-negative numbers, capping the result at math.MaxInt64
+func saturatingMul(a, b int64) int64 {
+	if a != 0 && b > math.MaxInt64/a {
+		return math.MaxInt64
+	}
+	return a * b
+}
@@ -0,0 +1,216 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLayoutCapacity(t *testing.T) {
+	tests := []struct {
+		name        string
+		layout      Layout
+		epoch       int64
+		exhaustedAt time.Time
+		nodes       int64
+		perWorker   int64
+		total       int64
+	}{
+		{
+			name:        "default",
+			layout:      DefaultLayout,
+			epoch:       DefaultEpoch,
+			exhaustedAt: time.Date(2094, 9, 7, 15, 47, 35, 552_000_000, time.UTC),
+			nodes:       1024,
+			perWorker:   4_096_000,
+			total:       4_194_304_000,
+		},
+		{
+			// 64-bit layout: int64 IDs stay positive only while the top timestamp bit is 0
+			name:        "discord",
+			layout:      DiscordLayout,
+			epoch:       DiscordEpoch,
+			exhaustedAt: time.UnixMilli(DiscordEpoch + 1<<41).UTC(),
+			nodes:       1024,
+			perWorker:   4_096_000,
+			total:       4_194_304_000,
+		},
+		{
+			// 64-bit layout, like Discord
+			name:        "instagram",
+			layout:      InstagramLayout,
+			epoch:       InstagramEpoch,
+			exhaustedAt: time.UnixMilli(InstagramEpoch + 1<<40).UTC(),
+			nodes:       8192,
+			perWorker:   1_024_000,
+			total:       8_388_608_000,
+		},
+		{
+			name:        "mastodon",
+			layout:      MastodonLayout,
+			epoch:       MastodonEpoch,
+			exhaustedAt: time.UnixMilli(1 << 47).UTC(),
+			nodes:       1,
+			perWorker:   65_536_000,
+			total:       65_536_000,
+		},
+		{
+			name:        "saturated throughput",
+			layout:      Layout{TimestampBits: 1, ProcessIDBits: 20, WorkerIDBits: 20, SequenceBits: 22},
+			epoch:       0,
+			exhaustedAt: time.UnixMilli(2).UTC(),
+			nodes:       1 << 40,
+			perWorker:   (1 << 22) * 1000,
+			total:       math.MaxInt64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.layout.Capacity(tt.epoch)
+			if err != nil {
+				t.Fatalf("Capacity() error = %v", err)
+			}
+			if !c.ExhaustedAt.Equal(tt.exhaustedAt) {
+				t.Errorf("ExhaustedAt = %v, want %v", c.ExhaustedAt, tt.exhaustedAt)
+			}
+			if !c.Epoch.Equal(time.UnixMilli(tt.epoch)) {
+				t.Errorf("Epoch = %v, want %v", c.Epoch, time.UnixMilli(tt.epoch))
+			}
+			if c.Nodes != tt.nodes {
+				t.Errorf("Nodes = %d, want %d", c.Nodes, tt.nodes)
+			}
+			if c.IDsPerSecondPerWorker != tt.perWorker {
+				t.Errorf("IDsPerSecondPerWorker = %d, want %d", c.IDsPerSecondPerWorker, tt.perWorker)
+			}
+			if c.IDsPerSecond != tt.total {
+				t.Errorf("IDsPerSecond = %d, want %d", c.IDsPerSecond, tt.total)
+			}
+		})
+	}
+
+	if _, err := (Layout{}).Capacity(0); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Capacity() on invalid layout error = %v, want ErrInvalidLayout", err)
+	}
+
+	// Lifetime saturates instead of overflowing
+	c, _ := MastodonLayout.Capacity(MastodonEpoch)
+	if c.Lifetime != time.Duration(math.MaxInt64) {
+		t.Errorf("Lifetime = %v, want saturated duration", c.Lifetime)
+	}
+}
+
+func TestRemainingLifetime(t *testing.T) {
+	generator, _ := New(1, 1)
+	c, _ := DefaultLayout.Capacity(DefaultEpoch)
+
+	remaining := generator.RemainingLifetime()
+	if want := time.Until(c.ExhaustedAt); remaining-want > time.Second || want-remaining > time.Second {
+		t.Errorf("RemainingLifetime() = %v, want about %v", remaining, want)
+	}
+
+	atomicGenerator, _ := NewAtomic(1, 1)
+	if got := atomicGenerator.RemainingLifetime(); got-remaining > time.Second || remaining-got > time.Second {
+		t.Errorf("AtomicSnowflake.RemainingLifetime() = %v, want about %v", got, remaining)
+	}
+
+	exhausted, _ := NewWithEpoch(1, 1, time.Now().UnixMilli()-1<<41)
+	if got := exhausted.RemainingLifetime(); got != 0 {
+		t.Errorf("RemainingLifetime() after exhaustion = %v, want 0", got)
+	}
+}
+
+func TestEpochExhausted(t *testing.T) {
+	// An epoch 2^41 ms in the past leaves no room in the timestamp field
+	epoch := time.Now().UnixMilli() - 1<<41
+
+	generator, _ := NewWithEpoch(1, 1, epoch)
+	if _, err := generator.GenerateContext(context.Background()); !errors.Is(err, ErrEpochExhausted) {
+		t.Errorf("GenerateContext() error = %v, want ErrEpochExhausted", err)
+	}
+	expectPanic(t, "Snowflake.Generate", func() { generator.Generate() })
+	expectPanic(t, "Snowflake.ReserveRange", func() { generator.ReserveRange(10) })
+	if _, err := generator.ReserveRangeContext(context.Background(), 10); !errors.Is(err, ErrEpochExhausted) {
+		t.Errorf("ReserveRangeContext() error = %v, want ErrEpochExhausted", err)
+	}
+	if _, err := generator.GenerateBatchE(10); !errors.Is(err, ErrEpochExhausted) {
+		t.Errorf("GenerateBatchE() error = %v, want ErrEpochExhausted", err)
+	}
+
+	atomicGenerator, _ := NewAtomicWithEpoch(1, 1, epoch)
+	if _, err := atomicGenerator.GenerateContext(context.Background()); !errors.Is(err, ErrEpochExhausted) {
+		t.Errorf("AtomicSnowflake.GenerateContext() error = %v, want ErrEpochExhausted", err)
+	}
+	expectPanic(t, "AtomicSnowflake.Generate", func() { atomicGenerator.Generate() })
+
+	// A 20-bit timestamp from now lasts about 17 minutes
+	short, _ := NewWithLayout(0, 0, Layout{TimestampBits: 20, SequenceBits: 12}, time.Now().UnixMilli())
+	if _, err := short.GenerateContext(context.Background()); err != nil {
+		t.Errorf("GenerateContext() within lifetime error = %v", err)
+	}
+
+	// A range that crosses the end of the lifetime is rejected without reserving anything
+	edge, _ := NewWithLayout(0, 0, Layout{TimestampBits: 20, SequenceBits: 2}, time.Now().UnixMilli()-1<<20+2)
+	expectPanic(t, "ReserveRange past lifetime", func() { edge.ReserveRange(100) })
+	if _, err := edge.GenerateContext(context.Background()); err != nil {
+		t.Errorf("GenerateContext() after rejected range error = %v", err)
+	}
+}
+
+// expectPanic fails the test unless fn panics with ErrEpochExhausted
+func expectPanic(t *testing.T, name string, fn func()) {
+	t.Helper()
+	expectPanicWith(t, name, ErrEpochExhausted, fn)
+}
+
+// expectPanicWith fails the test unless fn panics with an error wrapping want
+func expectPanicWith(t *testing.T, name string, want error, fn func()) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); !ok || !errors.Is(err, want) {
+			t.Errorf("%s: expected panic with %v, got %v", name, want, r)
+		}
+	}()
+	fn()
+}
+
+func TestClockBeforeEpoch(t *testing.T) {
+	// An epoch one hour ahead would make the timestamp field negative
+	epoch := time.Now().Add(time.Hour).UnixMilli()
+	if _, err := NewWithEpoch(1, 1, epoch); !errors.Is(err, ErrClockBeforeEpoch) {
+		t.Errorf("NewWithEpoch() with a future epoch error = %v, want ErrClockBeforeEpoch", err)
+	}
+
+	// The clock can still be set back past the epoch after construction
+	clock := &manualClock{now: time.UnixMilli(epoch + 1000)}
+	generator, err := NewSnowflakeGenerator(WithNode(1, 1), WithEpoch(epoch), WithClock(clock))
+	if err != nil {
+		t.Fatalf("NewSnowflakeGenerator() error = %v", err)
+	}
+	clock.Advance(-time.Hour)
+	if id, err := generator.GenerateContext(context.Background()); !errors.Is(err, ErrClockBeforeEpoch) {
+		t.Errorf("GenerateContext() = %d, %v, want ErrClockBeforeEpoch", id, err)
+	}
+	if _, err := generator.ReserveRangeContext(context.Background(), 10); !errors.Is(err, ErrClockBeforeEpoch) {
+		t.Errorf("ReserveRangeContext() error = %v, want ErrClockBeforeEpoch", err)
+	}
+	if _, err := generator.GenerateBatchE(10); !errors.Is(err, ErrClockBeforeEpoch) {
+		t.Errorf("GenerateBatchE() error = %v, want ErrClockBeforeEpoch", err)
+	}
+	expectPanicWith(t, "Snowflake.Generate", ErrClockBeforeEpoch, func() { generator.Generate() })
+
+	atomicGenerator, _ := NewAtomicWithEpoch(1, 1, epoch)
+	start := time.Now()
+	for i := 0; i <= maxSequence+1; i++ {
+		if id, err := atomicGenerator.GenerateContext(context.Background()); !errors.Is(err, ErrClockBeforeEpoch) {
+			t.Fatalf("AtomicSnowflake.GenerateContext() = %d, %v, want ErrClockBeforeEpoch", id, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("AtomicSnowflake.GenerateContext() waited %v before failing", elapsed)
+	}
+	expectPanicWith(t, "AtomicSnowflake.Generate", ErrClockBeforeEpoch, func() { atomicGenerator.Generate() })
+}
@@ -0,0 +1,224 @@
+package idgen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalidSnowflakeID is returned when a Snowflake ID cannot be parsed
+var ErrInvalidSnowflakeID = errors.New("invalid Snowflake ID")
+
+// SnowflakeID is a Snowflake ID with a consistent string representation.
+// It is interchangeable with the int64 values returned by the generators:
+//
+//	id := idgen.SnowflakeID(generator.Generate())
+//
+// By default SnowflakeID is encoded in JSON as a quoted decimal string,
+// because JavaScript numbers lose precision above 2^53 and Snowflake IDs
+// exceed that after a few days. See SetSnowflakeIDJSONFormat.
+type SnowflakeID int64
+
+// SnowflakeJSONFormat controls how SnowflakeID values are encoded in JSON
+type SnowflakeJSONFormat int32
+
+const (
+	// SnowflakeJSONString encodes IDs as quoted decimal strings: "175928847299117063"
+	SnowflakeJSONString SnowflakeJSONFormat = iota
+
+	// SnowflakeJSONNumber encodes IDs as bare JSON numbers: 175928847299117063
+	SnowflakeJSONNumber
+)
+
+var snowflakeJSONFormat atomic.Int32
+
+// SetSnowflakeIDJSONFormat sets how SnowflakeID.MarshalJSON encodes IDs for the whole process.
+// The default is SnowflakeJSONString. Decoding always accepts both forms.
+func SetSnowflakeIDJSONFormat(format SnowflakeJSONFormat) {
+	snowflakeJSONFormat.Store(int32(format))
+}
+
+// SnowflakeIDCodec formats and parses SnowflakeID values in an alternative textual form,
+// such as Sqids. See SetSnowflakeIDCodec.
+type SnowflakeIDCodec interface {
+	// FormatSnowflakeID returns the textual form of id
+	FormatSnowflakeID(id SnowflakeID) (string, error)
+
+	// ParseSnowflakeID parses a textual form returned by FormatSnowflakeID
+	ParseSnowflakeID(s string) (SnowflakeID, error)
+}
+
+// snowflakeCodecBox wraps the codec so it can be stored in an atomic.Pointer
+type snowflakeCodecBox struct {
+	codec SnowflakeIDCodec
+}
+
+var snowflakeIDCodec atomic.Pointer[snowflakeCodecBox]
+
+// SetSnowflakeIDCodec sets the textual form used by SnowflakeID text and JSON marshaling
+// for the whole process. Passing nil restores the default decimal form.
+//
+// With a codec set, MarshalText and quoted JSON strings use the codec, and
+// UnmarshalText and quoted JSON strings only accept the codec's form.
+// Bare JSON numbers (SnowflakeJSONNumber) and String are not affected.
+//
+// Example:
+//
+//	sqids, _ := idgen.NewSqids(idgen.SqidsOptions{MinLength: 8})
+//	idgen.SetSnowflakeIDCodec(sqids)
+//	payload, _ := json.Marshal(order) // order.ID is encoded as a Sqids string
+func SetSnowflakeIDCodec(codec SnowflakeIDCodec) {
+	if codec == nil {
+		snowflakeIDCodec.Store(nil)
+		return
+	}
+	snowflakeIDCodec.Store(&snowflakeCodecBox{codec: codec})
+}
+
+// loadSnowflakeIDCodec returns the configured codec, or nil for the decimal form
+func loadSnowflakeIDCodec() SnowflakeIDCodec {
+	if box := snowflakeIDCodec.Load(); box != nil {
+		return box.codec
+	}
+	return nil
+}
+
+// SnowflakeParts holds the decoded components of a Snowflake ID
+type SnowflakeParts struct {
+	// Timestamp is the creation time in milliseconds since Unix epoch
+	Timestamp int64
+
+	// ProcessID is the process identifier (0-31 with DefaultLayout)
+	ProcessID int64
+
+	// WorkerID is the worker identifier (0-31 with DefaultLayout)
+	WorkerID int64
+
+	// Sequence is the sequence number within the millisecond (0-4095 with DefaultLayout)
+	Sequence int64
+}
+
+// Time returns the creation time in UTC
+func (p SnowflakeParts) Time() time.Time {
+	return time.UnixMilli(p.Timestamp).UTC()
+}
+
+// ParseSnowflakeID parses a decimal Snowflake ID.
+//
+// Returns:
+//   - SnowflakeID: The parsed ID
+//   - error: ErrInvalidSnowflakeID if s is not a non-negative decimal int64
+func ParseSnowflakeID(s string) (SnowflakeID, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSnowflakeID, s)
+	}
+	return SnowflakeID(v), nil
+}
+
+// Int64 returns the ID as a plain int64
+func (id SnowflakeID) Int64() int64 {
+	return int64(id)
+}
+
+// String returns the decimal representation of the ID
+func (id SnowflakeID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// Time returns the creation time of the ID in UTC, for IDs generated with the given epoch.
+//
+// Parameters:
+//   - epoch: Epoch in milliseconds since Unix epoch used by the generator
+func (id SnowflakeID) Time(epoch int64) time.Time {
+	return time.UnixMilli((int64(id) >> timestampShift) + epoch).UTC()
+}
+
+// Components decodes the ID, assuming it was generated with DefaultLayout and DefaultEpoch.
+// Use DecodeSnowflake for other layouts and epochs.
+func (id SnowflakeID) Components() SnowflakeParts {
+	return decodeSnowflake(int64(id), DefaultLayout, DefaultEpoch)
+}
+
+// LogValue implements slog.LogValuer.
+// IDs are logged as decimal strings, so log pipelines that parse JSON numbers
+// as float64 do not lose precision above 2^53.
+func (id SnowflakeID) LogValue() slog.Value {
+	return slog.StringValue(id.String())
+}
+
+// MarshalText implements encoding.TextMarshaler using the decimal representation,
+// or the codec set with SetSnowflakeIDCodec
+func (id SnowflakeID) MarshalText() ([]byte, error) {
+	if codec := loadSnowflakeIDCodec(); codec != nil {
+		s, err := codec.FormatSnowflakeID(id)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	}
+	return strconv.AppendInt(nil, int64(id), 10), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using the decimal representation,
+// or the codec set with SetSnowflakeIDCodec
+func (id *SnowflakeID) UnmarshalText(text []byte) error {
+	parse := ParseSnowflakeID
+	if codec := loadSnowflakeIDCodec(); codec != nil {
+		parse = codec.ParseSnowflakeID
+	}
+
+	v, err := parse(string(text))
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The ID is encoded as a quoted string unless SetSnowflakeIDJSONFormat(SnowflakeJSONNumber) was called.
+func (id SnowflakeID) MarshalJSON() ([]byte, error) {
+	if SnowflakeJSONFormat(snowflakeJSONFormat.Load()) == SnowflakeJSONNumber {
+		return strconv.AppendInt(nil, int64(id), 10), nil
+	}
+	if codec := loadSnowflakeIDCodec(); codec != nil {
+		s, err := codec.FormatSnowflakeID(id)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(s)
+	}
+
+	buf := make([]byte, 0, 21)
+	buf = append(buf, '"')
+	buf = strconv.AppendInt(buf, int64(id), 10)
+	return append(buf, '"'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Both JSON numbers and quoted strings are accepted; null leaves the ID unchanged.
+// Quoted strings are decimal, or in the form of the codec set with SetSnowflakeIDCodec.
+func (id *SnowflakeID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		v, err := ParseSnowflakeID(string(data))
+		if err != nil {
+			return err
+		}
+		*id = v
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, err)
+	}
+	return id.UnmarshalText([]byte(s))
+}
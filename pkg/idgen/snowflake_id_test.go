@@ -0,0 +1,152 @@
+package idgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnowflakeIDString(t *testing.T) {
+	id := SnowflakeID(175928847299117063)
+	if got := id.String(); got != "175928847299117063" {
+		t.Errorf("String() = %q, want %q", got, "175928847299117063")
+	}
+	if id.Int64() != 175928847299117063 {
+		t.Errorf("Int64() = %d", id.Int64())
+	}
+}
+
+func TestLogValuers(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	uuid := UUID{0x01, 0x89, 0x0a, 0x5d, 0xac, 0x96, 0x77, 0x4b, 0xbc, 0xce, 0xb3, 0x02, 0x09, 0x9a, 0x80, 0x57}
+	logger.Info("ids", "uuid", uuid, "snowflake", SnowflakeID(9007199254740993))
+
+	out := buf.String()
+	if !strings.Contains(out, `"uuid":"01890a5d-ac96-774b-bcce-b302099a8057"`) {
+		t.Errorf("UUID not logged in canonical form: %s", out)
+	}
+	// 2^53 + 1 must survive as a string
+	if !strings.Contains(out, `"snowflake":"9007199254740993"`) {
+		t.Errorf("SnowflakeID not logged as string: %s", out)
+	}
+}
+
+func TestSnowflakeIDJSON(t *testing.T) {
+	type payload struct {
+		ID SnowflakeID `json:"id"`
+	}
+
+	// 2^53 + 1 is not representable as a float64
+	in := payload{ID: 9007199254740993}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != `{"id":"9007199254740993"}` {
+		t.Errorf("Marshal() = %s, want quoted string", data)
+	}
+
+	var out payload
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if out.ID != in.ID {
+		t.Errorf("Round trip = %d, want %d", out.ID, in.ID)
+	}
+}
+
+func TestSnowflakeIDJSONNumberFormat(t *testing.T) {
+	SetSnowflakeIDJSONFormat(SnowflakeJSONNumber)
+	defer SetSnowflakeIDJSONFormat(SnowflakeJSONString)
+
+	data, err := json.Marshal(SnowflakeID(42))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != "42" {
+		t.Errorf("Marshal() = %s, want 42", data)
+	}
+}
+
+func TestSnowflakeIDUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    SnowflakeID
+		wantErr bool
+	}{
+		{`"175928847299117063"`, 175928847299117063, false},
+		{`175928847299117063`, 175928847299117063, false},
+		{`null`, 7, false},
+		{`"abc"`, 0, true},
+		{`"-1"`, 0, true},
+		{`1.5`, 0, true},
+		{`"99999999999999999999"`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			id := SnowflakeID(7)
+			err := json.Unmarshal([]byte(tt.input), &id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSnowflakeID) {
+					t.Errorf("Unmarshal(%s) error = %v, want ErrInvalidSnowflakeID", tt.input, err)
+				}
+				return
+			}
+			if id != tt.want {
+				t.Errorf("Unmarshal(%s) = %d, want %d", tt.input, id, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnowflakeIDText(t *testing.T) {
+	m := map[SnowflakeID]string{123: "a"}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != `{"123":"a"}` {
+		t.Errorf("Marshal() map key = %s", data)
+	}
+
+	var id SnowflakeID
+	if err := id.UnmarshalText([]byte("456")); err != nil || id != 456 {
+		t.Errorf("UnmarshalText() = %d, %v", id, err)
+	}
+}
+
+func TestSnowflakeIDComponents(t *testing.T) {
+	generator, _ := New(10, 20)
+
+	before := time.Now().UnixMilli()
+	id := generator.GenerateID()
+	after := time.Now().UnixMilli()
+
+	parts := id.Components()
+	if parts.ProcessID != 10 || parts.WorkerID != 20 {
+		t.Errorf("Components() = %+v, want process 10 worker 20", parts)
+	}
+	if parts.Sequence != generator.ExtractSequence(id.Int64()) {
+		t.Errorf("Components().Sequence = %d, want %d", parts.Sequence, generator.ExtractSequence(id.Int64()))
+	}
+	if parts.Timestamp < before || parts.Timestamp > after {
+		t.Errorf("Components().Timestamp = %d, want between %d and %d", parts.Timestamp, before, after)
+	}
+	if !id.Time(DefaultEpoch).Equal(generator.ExtractTime(id.Int64())) {
+		t.Errorf("Time() = %v, want %v", id.Time(DefaultEpoch), generator.ExtractTime(id.Int64()))
+	}
+	if !parts.Time().Equal(id.Time(DefaultEpoch)) {
+		t.Errorf("SnowflakeParts.Time() = %v, want %v", parts.Time(), id.Time(DefaultEpoch))
+	}
+}
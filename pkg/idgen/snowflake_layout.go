@@ -0,0 +1,214 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidLayout is returned when a Layout does not describe a valid Snowflake ID
+var ErrInvalidLayout = errors.New("invalid Snowflake layout")
+
+// Errors returned by SnowflakeValidator, wrapped together with ErrInvalidSnowflakeID
+var (
+	// ErrSnowflakeNegative is returned for negative IDs
+	ErrSnowflakeNegative = errors.New("ID is negative")
+
+	// ErrSnowflakeInFuture is returned when the ID timestamp is ahead of the clock
+	ErrSnowflakeInFuture = errors.New("timestamp is in the future")
+
+	// ErrSnowflakeBeforeEpoch is returned when the ID timestamp is before the epoch
+	ErrSnowflakeBeforeEpoch = errors.New("timestamp is before the epoch")
+
+	// ErrSnowflakeOutsideLayout is returned when the ID sets bits above the layout
+	ErrSnowflakeOutsideLayout = errors.New("ID sets bits outside the layout")
+)
+
+// Layout describes how the bits of a Snowflake ID are split into fields.
+// Fields are packed from the most significant bit down:
+//
+//	[unused] [timestamp] [process ID] [worker ID] [sequence]
+//
+// Unused high bits are always 0. A field may have 0 bits when the scheme does not use it,
+// e.g. Instagram IDs have a single 13-bit shard ID, mapped to ProcessIDBits.
+type Layout struct {
+	// TimestampBits is the width of the millisecond timestamp, relative to the epoch
+	TimestampBits uint
+
+	// ProcessIDBits is the width of the process (or datacenter, shard) identifier
+	ProcessIDBits uint
+
+	// WorkerIDBits is the width of the worker identifier
+	WorkerIDBits uint
+
+	// SequenceBits is the width of the per-millisecond sequence
+	SequenceBits uint
+}
+
+// DefaultLayout is the layout used by New and NewWithEpoch:
+// 41 bits timestamp, 5 bits process ID, 5 bits worker ID and 12 bits sequence.
+var DefaultLayout = Layout{
+	TimestampBits: timestampBits,
+	ProcessIDBits: processIDBits,
+	WorkerIDBits:  workerIDBits,
+	SequenceBits:  sequenceBits,
+}
+
+// Validate reports whether the layout can be used to generate and decode IDs.
+// The timestamp must have at least one bit and all fields must fit in 64 bits.
+func (l Layout) Validate() error {
+	if l.TimestampBits == 0 {
+		return fmt.Errorf("%w: timestamp must have at least 1 bit", ErrInvalidLayout)
+	}
+	if total := l.TotalBits(); total > 64 {
+		return fmt.Errorf("%w: %d bits do not fit in 64", ErrInvalidLayout, total)
+	}
+	return nil
+}
+
+// TotalBits returns the number of bits used by all fields
+func (l Layout) TotalBits() uint {
+	return l.TimestampBits + l.ProcessIDBits + l.WorkerIDBits + l.SequenceBits
+}
+
+// MaxProcessID returns the largest process ID the layout can hold
+func (l Layout) MaxProcessID() int64 {
+	return int64(fieldMask(l.ProcessIDBits))
+}
+
+// MaxWorkerID returns the largest worker ID the layout can hold
+func (l Layout) MaxWorkerID() int64 {
+	return int64(fieldMask(l.WorkerIDBits))
+}
+
+// MaxSequence returns the largest sequence number the layout can hold
+func (l Layout) MaxSequence() int64 {
+	return int64(fieldMask(l.SequenceBits))
+}
+
+// workerIDShift returns the position of the worker ID field
+func (l Layout) workerIDShift() uint {
+	return l.SequenceBits
+}
+
+// processIDShift returns the position of the process ID field
+func (l Layout) processIDShift() uint {
+	return l.SequenceBits + l.WorkerIDBits
+}
+
+// timestampShift returns the position of the timestamp field
+func (l Layout) timestampShift() uint {
+	return l.SequenceBits + l.WorkerIDBits + l.ProcessIDBits
+}
+
+// fieldMask returns a mask with the low bits set
+func fieldMask(bits uint) uint64 {
+	if bits >= 64 {
+		return ^uint64(0)
+	}
+	return 1<<bits - 1
+}
+
+// DecodeSnowflake splits a Snowflake ID into its components without a generator instance.
+// This is useful to inspect IDs read back from a database or received from another system.
+//
+// The ID is decoded as an unsigned 64-bit value, so layouts that use all 64 bits decode
+// correctly even when the ID does not fit in a positive int64. Use ValidateSnowflake to
+// reject IDs that could not have been generated.
+//
+// Parameters:
+//   - id: A Snowflake ID
+//   - layout: The bit layout used to generate the ID
+//   - epoch: The epoch used to generate the ID, in milliseconds since Unix epoch
+//
+// Returns:
+//   - SnowflakeParts: The decoded components
+//   - error: ErrInvalidLayout if layout is invalid
+//
+// Example:
+//
+//	parts, err := idgen.DecodeSnowflake(id, idgen.DefaultLayout, idgen.DefaultEpoch)
+//	fmt.Println(parts.Time(), parts.ProcessID, parts.WorkerID, parts.Sequence)
+func DecodeSnowflake(id int64, layout Layout, epoch int64) (SnowflakeParts, error) {
+	if err := layout.Validate(); err != nil {
+		return SnowflakeParts{}, err
+	}
+	return decodeSnowflake(id, layout, epoch), nil
+}
+
+// decodeSnowflake decodes id with a layout that is known to be valid
+func decodeSnowflake(id int64, layout Layout, epoch int64) SnowflakeParts {
+	v := uint64(id)
+	return SnowflakeParts{
+		Timestamp: int64((v>>layout.timestampShift())&fieldMask(layout.TimestampBits)) + epoch,
+		ProcessID: int64((v >> layout.processIDShift()) & fieldMask(layout.ProcessIDBits)),
+		WorkerID:  int64((v >> layout.workerIDShift()) & fieldMask(layout.WorkerIDBits)),
+		Sequence:  int64(v & fieldMask(layout.SequenceBits)),
+	}
+}
+
+// SnowflakeValidator checks that IDs could have been produced by a generator
+// with the given layout and epoch.
+//
+// Example:
+//
+//	validator := idgen.SnowflakeValidator{
+//	    Layout:       idgen.DefaultLayout,
+//	    Epoch:        idgen.DefaultEpoch,
+//	    MaxClockSkew: time.Second,
+//	}
+//	if err := validator.Validate(id); err != nil {
+//	    return err
+//	}
+type SnowflakeValidator struct {
+	// Layout is the bit layout of the IDs
+	Layout Layout
+
+	// Epoch is the generator epoch in milliseconds since Unix epoch
+	Epoch int64
+
+	// MaxClockSkew is how far in the future an ID timestamp may be,
+	// to tolerate clock differences between machines
+	MaxClockSkew time.Duration
+
+	// Now returns the current time; time.Now is used when nil
+	Now func() time.Time
+}
+
+// Validate returns nil if id is a plausible Snowflake ID, or an error wrapping
+// ErrInvalidSnowflakeID and one of ErrSnowflakeNegative, ErrSnowflakeOutsideLayout,
+// ErrSnowflakeInFuture, ErrSnowflakeBeforeEpoch or ErrInvalidLayout.
+func (v SnowflakeValidator) Validate(id int64) error {
+	if err := v.Layout.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, err)
+	}
+	if id < 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, ErrSnowflakeNegative)
+	}
+	if total := v.Layout.TotalBits(); total < 64 && uint64(id)>>total != 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, ErrSnowflakeOutsideLayout)
+	}
+
+	parts := decodeSnowflake(id, v.Layout, v.Epoch)
+
+	// Only reachable when epoch + timestamp overflows int64
+	if parts.Timestamp < v.Epoch {
+		return fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, ErrSnowflakeBeforeEpoch)
+	}
+
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	if limit := now().Add(v.MaxClockSkew).UnixMilli(); parts.Timestamp > limit {
+		return fmt.Errorf("%w: %w (%d ms ahead)", ErrInvalidSnowflakeID, ErrSnowflakeInFuture, parts.Timestamp-limit)
+	}
+
+	return nil
+}
+
+// ValidateSnowflake checks id against layout and epoch without clock skew tolerance.
+// See SnowflakeValidator for the possible errors.
+func ValidateSnowflake(id int64, layout Layout, epoch int64) error {
+	return SnowflakeValidator{Layout: layout, Epoch: epoch}.Validate(id)
+}
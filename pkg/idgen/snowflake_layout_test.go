@@ -0,0 +1,145 @@
+package idgen
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLayoutValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  Layout
+		wantErr bool
+	}{
+		{"default", DefaultLayout, false},
+		{"64 bits", Layout{TimestampBits: 42, ProcessIDBits: 5, WorkerIDBits: 5, SequenceBits: 12}, false},
+		{"no node bits", Layout{TimestampBits: 48, SequenceBits: 16}, false},
+		{"no timestamp", Layout{SequenceBits: 12}, true},
+		{"too wide", Layout{TimestampBits: 48, ProcessIDBits: 5, WorkerIDBits: 5, SequenceBits: 12}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.layout.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidLayout) {
+				t.Errorf("Validate() error = %v, want ErrInvalidLayout", err)
+			}
+		})
+	}
+}
+
+func TestLayoutLimits(t *testing.T) {
+	if DefaultLayout.MaxProcessID() != maxProcessID {
+		t.Errorf("MaxProcessID() = %d, want %d", DefaultLayout.MaxProcessID(), maxProcessID)
+	}
+	if DefaultLayout.MaxWorkerID() != maxWorkerID {
+		t.Errorf("MaxWorkerID() = %d, want %d", DefaultLayout.MaxWorkerID(), maxWorkerID)
+	}
+	if DefaultLayout.MaxSequence() != maxSequence {
+		t.Errorf("MaxSequence() = %d, want %d", DefaultLayout.MaxSequence(), maxSequence)
+	}
+	if DefaultLayout.TotalBits() != 63 {
+		t.Errorf("TotalBits() = %d, want 63", DefaultLayout.TotalBits())
+	}
+
+	noWorker := Layout{TimestampBits: 41, ProcessIDBits: 13, SequenceBits: 10}
+	if noWorker.MaxWorkerID() != 0 {
+		t.Errorf("MaxWorkerID() with 0 bits = %d, want 0", noWorker.MaxWorkerID())
+	}
+}
+
+func TestDecodeSnowflake(t *testing.T) {
+	generator, _ := New(17, 29)
+	id := generator.Generate()
+
+	parts, err := DecodeSnowflake(id, DefaultLayout, DefaultEpoch)
+	if err != nil {
+		t.Fatalf("DecodeSnowflake() error = %v", err)
+	}
+
+	want := SnowflakeParts{
+		Timestamp: generator.ExtractTimestamp(id),
+		ProcessID: generator.ExtractProcessID(id),
+		WorkerID:  generator.ExtractWorkerID(id),
+		Sequence:  generator.ExtractSequence(id),
+	}
+	if parts != want {
+		t.Errorf("DecodeSnowflake() = %+v, want %+v", parts, want)
+	}
+	if !parts.Time().Equal(generator.ExtractTime(id)) {
+		t.Errorf("Time() = %v, want %v", parts.Time(), generator.ExtractTime(id))
+	}
+}
+
+func TestDecodeSnowflakeInvalidLayout(t *testing.T) {
+	_, err := DecodeSnowflake(1, Layout{}, 0)
+	if !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("DecodeSnowflake() error = %v, want ErrInvalidLayout", err)
+	}
+}
+
+func TestValidateSnowflake(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	elapsed := now.UnixMilli() - DefaultEpoch
+	idAt := func(offset int64) int64 {
+		return (elapsed + offset) << timestampShift
+	}
+	// 52 bits in total, so the top 12 bits of an ID must be clear
+	narrow := Layout{TimestampBits: 30, ProcessIDBits: 5, WorkerIDBits: 5, SequenceBits: 12}
+	narrowEpoch := now.UnixMilli() - 1000
+
+	tests := []struct {
+		name    string
+		id      int64
+		layout  Layout
+		epoch   int64
+		skew    time.Duration
+		wantErr error
+	}{
+		{"valid", idAt(-1000), DefaultLayout, DefaultEpoch, 0, nil},
+		{"now", idAt(0), DefaultLayout, DefaultEpoch, 0, nil},
+		{"negative", -1, DefaultLayout, DefaultEpoch, 0, ErrSnowflakeNegative},
+		{"future", idAt(1), DefaultLayout, DefaultEpoch, 0, ErrSnowflakeInFuture},
+		{"future within skew", idAt(500), DefaultLayout, DefaultEpoch, time.Second, nil},
+		{"future beyond skew", idAt(1500), DefaultLayout, DefaultEpoch, time.Second, ErrSnowflakeInFuture},
+		{"timestamp overflows epoch", 100 << timestampShift, DefaultLayout, math.MaxInt64 - 10, 0, ErrSnowflakeBeforeEpoch},
+		{"narrow layout", 1000 << 22, narrow, narrowEpoch, 0, nil},
+		{"bits above narrow layout", 1<<60 | 1000<<22, narrow, narrowEpoch, 0, ErrSnowflakeOutsideLayout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := SnowflakeValidator{
+				Layout:       tt.layout,
+				Epoch:        tt.epoch,
+				MaxClockSkew: tt.skew,
+				Now:          func() time.Time { return now },
+			}
+			err := validator.Validate(tt.id)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrInvalidSnowflakeID) {
+				t.Errorf("Validate() error = %v, want %v wrapped in ErrInvalidSnowflakeID", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSnowflakeGenerated(t *testing.T) {
+	generator, _ := New(1, 1)
+	if err := ValidateSnowflake(generator.Generate(), DefaultLayout, DefaultEpoch); err != nil {
+		t.Errorf("ValidateSnowflake() on fresh ID error = %v", err)
+	}
+	if err := ValidateSnowflake(1, Layout{}, DefaultEpoch); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("ValidateSnowflake() with invalid layout error = %v, want ErrInvalidLayout", err)
+	}
+}
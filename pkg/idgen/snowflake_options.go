@@ -0,0 +1,203 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ErrInvalidOption is wrapped by OptionError when an option argument is unusable,
+// such as a nil Clock or an unknown ClockPolicy
+var ErrInvalidOption = errors.New("invalid option")
+
+// Option configures a Snowflake generator created with NewSnowflakeGenerator
+type Option func(*snowflakeOptions) error
+
+// snowflakeOptions collects the options before the generator is built
+type snowflakeOptions struct {
+	processID   int64
+	workerID    int64
+	layout      Layout
+	epoch       int64
+	clock       Clock
+	clockPolicy ClockPolicy
+	observer    Observer
+	logger      *slog.Logger
+	store       StateStore
+}
+
+// WithNode sets the process and worker IDs of the generator. Both default to 0.
+// They are checked against the layout once all options are applied,
+// so WithNode and WithLayout can be passed in any order.
+func WithNode(processID, workerID int64) Option {
+	return func(o *snowflakeOptions) error {
+		o.processID, o.workerID = processID, workerID
+		return nil
+	}
+}
+
+// WithEpoch sets the epoch in milliseconds since the Unix epoch. The default is DefaultEpoch.
+// The epoch must not be ahead of the clock, which would make the timestamps negative;
+// it is checked once all options are applied, so WithClock can come later.
+func WithEpoch(epoch int64) Option {
+	return func(o *snowflakeOptions) error {
+		o.epoch = epoch
+		return nil
+	}
+}
+
+// WithLayout sets the bit layout of the generated IDs. The default is DefaultLayout.
+func WithLayout(layout Layout) Option {
+	return func(o *snowflakeOptions) error {
+		if err := layout.Validate(); err != nil {
+			return &OptionError{Option: "WithLayout", Err: err}
+		}
+		o.layout = layout
+		return nil
+	}
+}
+
+// WithClock sets the time source of the generator. The default is SystemClock.
+func WithClock(c Clock) Option {
+	return func(o *snowflakeOptions) error {
+		if c == nil {
+			return &OptionError{Option: "WithClock", Err: fmt.Errorf("%w: nil Clock", ErrInvalidOption)}
+		}
+		o.clock = c
+		return nil
+	}
+}
+
+// WithClockPolicy sets how the generator reacts when the clock moves backwards.
+// The default is ClockWait.
+func WithClockPolicy(p ClockPolicy) Option {
+	return func(o *snowflakeOptions) error {
+		if p != ClockWait && p != ClockFail {
+			return &OptionError{Option: "WithClockPolicy", Err: fmt.Errorf("%w: unknown %v", ErrInvalidOption, p)}
+		}
+		o.clockPolicy = p
+		return nil
+	}
+}
+
+// WithObserver attaches an Observer, like Snowflake.SetObserver
+func WithObserver(obs Observer) Option {
+	return func(o *snowflakeOptions) error {
+		o.observer = obs
+		return nil
+	}
+}
+
+// WithLogger attaches a structured logger, like Snowflake.SetLogger
+func WithLogger(l *slog.Logger) Option {
+	return func(o *snowflakeOptions) error {
+		o.logger = l
+		return nil
+	}
+}
+
+// WithStateStore makes the generator persist its last timestamp, so IDs stay
+// unique across restarts even if the clock was set back while the process was down.
+// See StateStore for how the timestamp is saved.
+//
+// A failing store makes the generator fail rather than issue IDs past the saved bound.
+// The error-returning methods (GenerateContext, GenerateBatchE, ReserveRangeContext)
+// return an error wrapping ErrStateStore, but Generate, GenerateBatch and ReserveRange
+// panic with it. Use only the error-returning methods with a store.
+func WithStateStore(store StateStore) Option {
+	return func(o *snowflakeOptions) error {
+		if store == nil {
+			return &OptionError{Option: "WithStateStore", Err: fmt.Errorf("%w: nil StateStore", ErrInvalidOption)}
+		}
+		o.store = store
+		return nil
+	}
+}
+
+// NewSnowflakeGenerator creates a new Snowflake ID generator from functional options.
+// Without options it is equivalent to New(0, 0).
+//
+// Options are applied in order, then checked together: node IDs must fit in the
+// layout, the epoch must not be ahead of the clock, and a state store must hold
+// a timestamp the layout can still represent.
+// The first problem is returned as an *OptionError naming the option.
+//
+// Parameters:
+//   - opts: Options such as WithNode, WithEpoch, WithLayout, WithClock, WithClockPolicy,
+//     WithObserver, WithLogger and WithStateStore
+//
+// Returns:
+//   - *Snowflake: A new ID generator instance
+//   - error: *OptionError wrapping ErrInvalidLayout, ErrInvalidProcessID, ErrInvalidWorkerID,
+//     ErrClockBeforeEpoch, ErrInvalidOption, ErrEpochExhausted or ErrStateStore
+//
+// Example:
+//
+//	generator, err := idgen.NewSnowflakeGenerator(
+//	    idgen.WithNode(5, 12),
+//	    idgen.WithLayout(idgen.DiscordLayout),
+//	    idgen.WithEpoch(idgen.DiscordEpoch),
+//	    idgen.WithClockPolicy(idgen.ClockFail),
+//	    idgen.WithLogger(slog.Default()),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func NewSnowflakeGenerator(opts ...Option) (*Snowflake, error) {
+	o := snowflakeOptions{layout: DefaultLayout, epoch: DefaultEpoch}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.processID < 0 || o.processID > o.layout.MaxProcessID() {
+		return nil, &OptionError{Option: "WithNode", Err: ErrInvalidProcessID}
+	}
+	if o.workerID < 0 || o.workerID > o.layout.MaxWorkerID() {
+		return nil, &OptionError{Option: "WithNode", Err: ErrInvalidWorkerID}
+	}
+
+	clock := o.clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	if now := clock.Now().UnixMilli(); o.epoch > now {
+		ahead := time.Duration(o.epoch-now) * time.Millisecond
+		return nil, &OptionError{Option: "WithEpoch", Err: fmt.Errorf("%w: epoch is %v ahead of the clock", ErrClockBeforeEpoch, ahead)}
+	}
+
+	s := &Snowflake{
+		layout:      o.layout,
+		epoch:       o.epoch,
+		processID:   o.processID,
+		workerID:    o.workerID,
+		node:        (o.processID << o.layout.processIDShift()) | (o.workerID << o.layout.workerIDShift()),
+		clock:       o.clock,
+		clockPolicy: o.clockPolicy,
+		store:       o.store,
+	}
+	if o.observer != nil {
+		s.SetObserver(o.observer)
+	}
+	if o.logger != nil {
+		s.SetLogger(o.logger)
+	}
+
+	if o.store != nil {
+		saved, err := o.store.Load(context.Background())
+		if err != nil {
+			return nil, &OptionError{Option: "WithStateStore", Err: fmt.Errorf("%w: %w", ErrStateStore, err)}
+		}
+		if saved-o.epoch > o.layout.timestampLimit() {
+			return nil, &OptionError{Option: "WithStateStore", Err: fmt.Errorf("%w: saved timestamp %d", ErrEpochExhausted, saved)}
+		}
+		// Resume after the last millisecond that may have been used before the restart
+		s.lastTimestamp = saved
+		s.sequence = o.layout.MaxSequence()
+		s.persistedUntil = saved
+	}
+	return s, nil
+}
@@ -0,0 +1,254 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock that only moves when told to
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// memoryStateStore is a StateStore that records saves and can be told to fail
+type memoryStateStore struct {
+	saved int64
+	saves int
+	err   error
+}
+
+func (m *memoryStateStore) Load(ctx context.Context) (int64, error) {
+	return m.saved, m.err
+}
+
+func (m *memoryStateStore) Save(ctx context.Context, timestamp int64) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.saved = timestamp
+	m.saves++
+	return nil
+}
+
+func TestNewSnowflakeGeneratorErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantOption string
+		wantErr    error
+	}{
+		{"invalid layout", []Option{WithLayout(Layout{SequenceBits: 12})}, "WithLayout", ErrInvalidLayout},
+		{"process out of range", []Option{WithNode(32, 0)}, "WithNode", ErrInvalidProcessID},
+		{"worker out of range", []Option{WithNode(0, -1)}, "WithNode", ErrInvalidWorkerID},
+		{"node checked against later layout", []Option{WithNode(2, 0), WithLayout(Layout{TimestampBits: 41, ProcessIDBits: 1, SequenceBits: 12})}, "WithNode", ErrInvalidProcessID},
+		{"nil clock", []Option{WithClock(nil)}, "WithClock", ErrInvalidOption},
+		{"unknown clock policy", []Option{WithClockPolicy(ClockPolicy(7))}, "WithClockPolicy", ErrInvalidOption},
+		{"nil state store", []Option{WithStateStore(nil)}, "WithStateStore", ErrInvalidOption},
+		{"state store load fails", []Option{WithStateStore(&memoryStateStore{err: errors.New("disk on fire")})}, "WithStateStore", ErrStateStore},
+		{"epoch ahead of the clock", []Option{WithEpoch(time.Now().Add(time.Minute).UnixMilli())}, "WithEpoch", ErrClockBeforeEpoch},
+		{"epoch ahead of a later clock", []Option{WithEpoch(DefaultEpoch), WithClock(&manualClock{now: time.UnixMilli(DefaultEpoch - 1)})}, "WithEpoch", ErrClockBeforeEpoch},
+		{"saved timestamp past lifetime", []Option{WithLayout(Layout{TimestampBits: 20, SequenceBits: 12}), WithStateStore(&memoryStateStore{saved: DefaultEpoch + 1<<21})}, "WithStateStore", ErrEpochExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewSnowflakeGenerator(tt.opts...)
+			if g != nil {
+				t.Errorf("NewSnowflakeGenerator() = %v, want nil", g)
+			}
+			var optErr *OptionError
+			if !errors.As(err, &optErr) {
+				t.Fatalf("NewSnowflakeGenerator() error = %v, want *OptionError", err)
+			}
+			if optErr.Option != tt.wantOption {
+				t.Errorf("OptionError.Option = %q, want %q", optErr.Option, tt.wantOption)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewSnowflakeGenerator() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewSnowflakeGeneratorOptions(t *testing.T) {
+	observer := newRecordingObserver()
+	clock := &manualClock{now: time.UnixMilli(DiscordEpoch + 5000)}
+	layout := Layout{TimestampBits: 41, ProcessIDBits: 10, SequenceBits: 12}
+
+	g, err := NewSnowflakeGenerator(
+		WithLayout(layout),
+		WithNode(1000, 0),
+		WithEpoch(DiscordEpoch),
+		WithClock(clock),
+		WithClockPolicy(ClockFail),
+		WithObserver(observer),
+	)
+	if err != nil {
+		t.Fatalf("NewSnowflakeGenerator() error = %v", err)
+	}
+	if g.Layout() != layout || g.Epoch() != DiscordEpoch || g.ProcessID() != 1000 || g.ClockPolicy() != ClockFail {
+		t.Errorf("generator = layout %v epoch %d process %d policy %v", g.Layout(), g.Epoch(), g.ProcessID(), g.ClockPolicy())
+	}
+
+	id, err := g.GenerateContext(context.Background())
+	if err != nil {
+		t.Fatalf("GenerateContext() error = %v", err)
+	}
+	if got := g.ExtractTimestamp(id); got != DiscordEpoch+5000 {
+		t.Errorf("ExtractTimestamp() = %d, want the clock time %d", got, DiscordEpoch+5000)
+	}
+	if observer.generated[GeneratorSnowflake] != 1 {
+		t.Errorf("observer saw %d IDs, want 1", observer.generated[GeneratorSnowflake])
+	}
+
+	clock.Advance(-10 * time.Millisecond)
+	if _, err := g.GenerateContext(context.Background()); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Errorf("GenerateContext() after regression error = %v, want ErrClockMovedBackwards", err)
+	}
+}
+
+func TestSnowflakeStateStore(t *testing.T) {
+	clock := &manualClock{now: time.UnixMilli(DefaultEpoch + 3600000)}
+	store := &memoryStateStore{}
+
+	g, err := NewSnowflakeGenerator(WithClock(clock), WithStateStore(store))
+	if err != nil {
+		t.Fatalf("NewSnowflakeGenerator() error = %v", err)
+	}
+	first := g.Generate()
+	g.Generate()
+	if store.saves != 1 || store.saved != DefaultEpoch+3600000+StateHorizon.Milliseconds() {
+		t.Fatalf("after two IDs: %d saves of %d, want 1 save one horizon ahead", store.saves, store.saved)
+	}
+
+	// A restarted generator resumes after the saved horizon, even with the clock set back
+	clock.Advance(-time.Minute)
+	restarted, err := NewSnowflakeGenerator(WithClock(clock), WithStateStore(store))
+	if err != nil {
+		t.Fatalf("NewSnowflakeGenerator() after restart error = %v", err)
+	}
+	horizon := store.saved
+	r := restarted.ReserveRange(3)
+	if got := restarted.ExtractTimestamp(r.Start); got != horizon+1 {
+		t.Errorf("restarted range starts at %d, want %d", got, horizon+1)
+	}
+	if r.Start <= first {
+		t.Errorf("restarted range start %d <= earlier ID %d", r.Start, first)
+	}
+
+	store.err = errors.New("disk full")
+	clock.Advance(2 * time.Minute)
+	if _, err := restarted.GenerateContext(context.Background()); !errors.Is(err, ErrStateStore) {
+		t.Errorf("GenerateContext() with failing store error = %v, want ErrStateStore", err)
+	}
+	if r, err := restarted.ReserveRangeContext(context.Background(), 10); !errors.Is(err, ErrStateStore) || r.Count != 0 {
+		t.Errorf("ReserveRangeContext() with failing store = %v, %v, want empty range and ErrStateStore", r, err)
+	}
+	if ids, err := restarted.GenerateBatchE(10); !errors.Is(err, ErrStateStore) || ids != nil {
+		t.Errorf("GenerateBatchE() with failing store = %v, %v, want nil and ErrStateStore", ids, err)
+	}
+
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrStateStore) {
+			t.Errorf("ReserveRange() with failing store: expected panic with ErrStateStore, got %v", err)
+		}
+	}()
+	restarted.ReserveRange(1)
+}
+
+func TestFileStateStore(t *testing.T) {
+	store := FileStateStore{Path: filepath.Join(t.TempDir(), "snowflake.state")}
+	ctx := context.Background()
+
+	if got, err := store.Load(ctx); got != 0 || err != nil {
+		t.Fatalf("Load() of missing file = %d, %v, want 0, nil", got, err)
+	}
+	if err := store.Save(ctx, 1700000000000); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if got, err := store.Load(ctx); got != 1700000000000 || err != nil {
+		t.Errorf("Load() = %d, %v, want 1700000000000, nil", got, err)
+	}
+	if err := store.Save(ctx, 1700000001000); err != nil {
+		t.Fatalf("second Save() error = %v", err)
+	}
+	if got, _ := store.Load(ctx); got != 1700000001000 {
+		t.Errorf("Load() after overwrite = %d, want 1700000001000", got)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(store.Path)); len(entries) != 1 {
+		t.Errorf("directory holds %d files after Save, want no temporary leftovers", len(entries))
+	}
+}
+
+func TestFileStateStoreCorrupt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"empty", ""},
+		{"newline only", "\n"},
+		{"truncated", "17000"},
+		{"garbage", "17000000\x00\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := FileStateStore{Path: filepath.Join(t.TempDir(), "snowflake.state")}
+			if err := os.WriteFile(store.Path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := store.Load(context.Background()); err == nil {
+				t.Errorf("Load() of %q expected error", tt.content)
+			}
+			if _, err := NewSnowflakeGenerator(WithStateStore(store)); !errors.Is(err, ErrStateStore) {
+				t.Errorf("NewSnowflakeGenerator() error = %v, want ErrStateStore", err)
+			}
+		})
+	}
+}
+
+func TestLegacyConstructorsReturnSentinels(t *testing.T) {
+	narrow := Layout{TimestampBits: 41, ProcessIDBits: 1, WorkerIDBits: 1, SequenceBits: 12}
+	tests := []struct {
+		name    string
+		create  func() (*Snowflake, error)
+		wantErr error
+	}{
+		{"New process", func() (*Snowflake, error) { return New(32, 0) }, ErrInvalidProcessID},
+		{"New worker", func() (*Snowflake, error) { return New(0, 32) }, ErrInvalidWorkerID},
+		{"NewWithEpoch process", func() (*Snowflake, error) { return NewWithEpoch(-1, 0, DefaultEpoch) }, ErrInvalidProcessID},
+		{"NewWithLayout worker", func() (*Snowflake, error) { return NewWithLayout(1, 2, narrow, DefaultEpoch) }, ErrInvalidWorkerID},
+		{"NewSnowflake process", func() (*Snowflake, error) { return NewSnowflake(99, 0) }, ErrInvalidProcessID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := tt.create()
+			if g != nil {
+				t.Errorf("generator = %v, want nil", g)
+			}
+			// Callers compare with ==, so the sentinel must not be wrapped
+			if err != tt.wantErr {
+				t.Errorf("error = %v, want exactly %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrInvalidPoolSize is returned when a SnowflakePool is created with an invalid number of workers
+var ErrInvalidPoolSize = errors.New("pool size must be between 1 and 32")
+
+// ErrInvalidPoolStrategy is returned when a SnowflakePool is created with an unknown PoolStrategy
+var ErrInvalidPoolStrategy = errors.New("pool strategy must be PoolRoundRobin or PoolAffinity")
+
+// PoolStrategy controls how a SnowflakePool distributes calls across its workers
+type PoolStrategy int
+
+const (
+	// PoolRoundRobin hands out workers in strict rotation
+	PoolRoundRobin PoolStrategy = iota
+
+	// PoolAffinity keeps a worker cached per P (logical processor), so goroutines
+	// running on the same P keep reusing the same worker and rarely contend.
+	// Workers are assigned round-robin when a P has no cached worker.
+	PoolAffinity
+)
+
+// SnowflakePool owns several Snowflake generators that share one process ID
+// and differ only by worker ID.
+//
+// A single Snowflake serializes every caller on one mutex and is capped at
+// 4096 IDs per millisecond. The pool spreads calls across up to 32 workers,
+// so throughput grows with the number of workers until the CPUs are saturated.
+//
+// All IDs produced by the pool are unique and decode with the standard
+// Snowflake layout. IDs from the same worker are strictly increasing; IDs
+// from different workers are only ordered by millisecond.
+type SnowflakePool struct {
+	processID int64
+	strategy  PoolStrategy
+	workers   []*Snowflake
+	next      atomic.Uint64
+	affinity  sync.Pool
+}
+
+// NewSnowflakePool creates a pool of Snowflake generators for one process.
+// The pool uses worker IDs 0 through workers-1.
+//
+// Parameters:
+//   - processID: Unique process identifier (0-31)
+//   - workers: Number of workers owned by the pool (1-32)
+//   - strategy: How calls are distributed across workers
+//
+// Returns:
+//   - *SnowflakePool: A new pool instance
+//   - error: ErrInvalidProcessID, ErrInvalidPoolSize or ErrInvalidPoolStrategy if parameters are invalid
+//
+// Example:
+//
+//	pool, err := idgen.NewSnowflakePool(5, 32, idgen.PoolAffinity)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	id := pool.Generate()
+func NewSnowflakePool(processID int64, workers int, strategy PoolStrategy) (*SnowflakePool, error) {
+	if workers < 1 || workers > maxWorkerID+1 {
+		return nil, ErrInvalidPoolSize
+	}
+	if strategy != PoolRoundRobin && strategy != PoolAffinity {
+		return nil, ErrInvalidPoolStrategy
+	}
+
+	p := &SnowflakePool{
+		processID: processID,
+		strategy:  strategy,
+		workers:   make([]*Snowflake, workers),
+	}
+	for i := range p.workers {
+		generator, err := New(processID, int64(i))
+		if err != nil {
+			return nil, err
+		}
+		p.workers[i] = generator
+	}
+	p.affinity.New = func() any {
+		return p.roundRobin()
+	}
+
+	return p, nil
+}
+
+// Generate creates a new unique Snowflake ID using one of the pool's workers.
+// This method is thread-safe.
+//
+// Returns:
+//   - int64: A unique 64-bit Snowflake ID
+func (p *SnowflakePool) Generate() int64 {
+	worker := p.acquire()
+	id := worker.Generate()
+	p.release(worker)
+	return id
+}
+
+// GenerateContext creates a new unique Snowflake ID like Generate, but stops waiting
+// for the clock when ctx is canceled or its deadline would expire.
+//
+// Returns:
+//   - int64: A unique 64-bit Snowflake ID
+//   - error: *WaitCanceledError if ctx ended while waiting, or ErrEpochExhausted
+func (p *SnowflakePool) GenerateContext(ctx context.Context) (int64, error) {
+	worker := p.acquire()
+	id, err := worker.GenerateContext(ctx)
+	p.release(worker)
+	return id, err
+}
+
+// GenerateString creates a new Snowflake ID in decimal form, implementing Generator
+func (p *SnowflakePool) GenerateString(ctx context.Context) (string, error) {
+	id, err := p.GenerateContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(id, 10), nil
+}
+
+// GenerateBatch generates multiple IDs from a single worker,
+// so the returned IDs are strictly increasing.
+//
+// Parameters:
+//   - count: Number of IDs to generate
+//
+// Returns:
+//   - []int64: Slice of unique Snowflake IDs
+func (p *SnowflakePool) GenerateBatch(count int) []int64 {
+	worker := p.acquire()
+	ids := worker.GenerateBatch(count)
+	p.release(worker)
+	return ids
+}
+
+// SetObserver attaches an Observer to every worker in the pool.
+// Passing nil restores the default no-op observer.
+func (p *SnowflakePool) SetObserver(o Observer) {
+	for _, worker := range p.workers {
+		worker.SetObserver(o)
+	}
+}
+
+// SetLogger attaches a structured logger to every worker in the pool.
+// Passing nil disables logging.
+func (p *SnowflakePool) SetLogger(l *slog.Logger) {
+	for _, worker := range p.workers {
+		worker.SetLogger(l)
+	}
+}
+
+// acquire picks the worker for the next call
+func (p *SnowflakePool) acquire() *Snowflake {
+	if p.strategy == PoolAffinity {
+		return p.affinity.Get().(*Snowflake)
+	}
+	return p.roundRobin()
+}
+
+// release returns a worker obtained from acquire
+func (p *SnowflakePool) release(worker *Snowflake) {
+	if p.strategy == PoolAffinity {
+		p.affinity.Put(worker)
+	}
+}
+
+// roundRobin returns the next worker in rotation
+func (p *SnowflakePool) roundRobin() *Snowflake {
+	n := p.next.Add(1) - 1
+	return p.workers[n%uint64(len(p.workers))]
+}
+
+// ProcessID returns the process ID shared by all workers in the pool.
+func (p *SnowflakePool) ProcessID() int64 {
+	return p.processID
+}
+
+// Size returns the number of workers owned by the pool.
+func (p *SnowflakePool) Size() int {
+	return len(p.workers)
+}
+
+// Workers returns the generators owned by the pool, indexed by worker ID.
+// The returned slice is a copy; the generators themselves are shared.
+func (p *SnowflakePool) Workers() []*Snowflake {
+	workers := make([]*Snowflake, len(p.workers))
+	copy(workers, p.workers)
+	return workers
+}
@@ -0,0 +1,152 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewSnowflakePool(t *testing.T) {
+	tests := []struct {
+		name        string
+		processID   int64
+		workers     int
+		strategy    PoolStrategy
+		expectError error
+	}{
+		{"single worker", 0, 1, PoolRoundRobin, nil},
+		{"all workers", 31, 32, PoolAffinity, nil},
+		{"zero workers", 0, 0, PoolRoundRobin, ErrInvalidPoolSize},
+		{"too many workers", 0, 33, PoolRoundRobin, ErrInvalidPoolSize},
+		{"invalid process ID", 32, 4, PoolRoundRobin, ErrInvalidProcessID},
+		{"unknown strategy", 0, 4, PoolStrategy(2), ErrInvalidPoolStrategy},
+		{"negative strategy", 0, 4, PoolStrategy(-1), ErrInvalidPoolStrategy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, err := NewSnowflakePool(tt.processID, tt.workers, tt.strategy)
+			if err != tt.expectError {
+				t.Fatalf("Expected error %v, got %v", tt.expectError, err)
+			}
+			if err != nil {
+				return
+			}
+			if pool.Size() != tt.workers {
+				t.Errorf("Expected %d workers, got %d", tt.workers, pool.Size())
+			}
+			for i, worker := range pool.Workers() {
+				if worker.WorkerID() != int64(i) {
+					t.Errorf("Expected worker ID %d, got %d", i, worker.WorkerID())
+				}
+				if worker.ProcessID() != tt.processID {
+					t.Errorf("Expected process ID %d, got %d", tt.processID, worker.ProcessID())
+				}
+			}
+		})
+	}
+}
+
+func TestSnowflakePoolRoundRobin(t *testing.T) {
+	pool, err := NewSnowflakePool(2, 4, PoolRoundRobin)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+
+	decoder, _ := New(0, 0)
+	for i := 0; i < 8; i++ {
+		id := pool.Generate()
+		if got := decoder.ExtractWorkerID(id); got != int64(i%4) {
+			t.Errorf("Call %d: expected worker %d, got %d", i, i%4, got)
+		}
+		if got := decoder.ExtractProcessID(id); got != 2 {
+			t.Errorf("Call %d: expected process 2, got %d", i, got)
+		}
+	}
+}
+
+func TestSnowflakePoolBatch(t *testing.T) {
+	pool, _ := NewSnowflakePool(1, 8, PoolAffinity)
+
+	ids := pool.GenerateBatch(5000)
+	if len(ids) != 5000 {
+		t.Fatalf("Expected 5000 IDs, got %d", len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("Batch not strictly increasing at index %d", i)
+		}
+	}
+}
+
+func TestSnowflakePoolConcurrency(t *testing.T) {
+	for _, strategy := range []PoolStrategy{PoolRoundRobin, PoolAffinity} {
+		t.Run(fmt.Sprintf("strategy=%d", strategy), func(t *testing.T) {
+			pool, _ := NewSnowflakePool(3, 32, strategy)
+
+			const goroutines = 64
+			const idsPerGoroutine = 500
+
+			var wg sync.WaitGroup
+			results := make([][]int64, goroutines)
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					ids := make([]int64, idsPerGoroutine)
+					for j := range ids {
+						ids[j] = pool.Generate()
+					}
+					results[i] = ids
+				}(i)
+			}
+			wg.Wait()
+
+			seen := make(map[int64]bool, goroutines*idsPerGoroutine)
+			for _, ids := range results {
+				for _, id := range ids {
+					if seen[id] {
+						t.Fatalf("Duplicate ID in concurrent generation: %d", id)
+					}
+					seen[id] = true
+				}
+			}
+		})
+	}
+}
+
+func TestSnowflakePoolGenerateContext(t *testing.T) {
+	pool, _ := NewSnowflakePool(1, 2, PoolRoundRobin)
+
+	if _, err := pool.GenerateContext(context.Background()); err != nil {
+		t.Fatalf("GenerateContext() error = %v", err)
+	}
+
+	// Force every worker to wait for the clock
+	for _, worker := range pool.Workers() {
+		worker.lastTimestamp = time.Now().UnixMilli() + 1000
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := pool.GenerateContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func BenchmarkSnowflakePoolContention(b *testing.B) {
+	for _, goroutines := range []int{1, 8, 64, 256} {
+		b.Run(fmt.Sprintf("round-robin/goroutines=%d", goroutines), func(b *testing.B) {
+			pool, _ := NewSnowflakePool(1, 32, PoolRoundRobin)
+			benchmarkContention(b, goroutines, pool.Generate)
+		})
+		b.Run(fmt.Sprintf("affinity/goroutines=%d", goroutines), func(b *testing.B) {
+			pool, _ := NewSnowflakePool(1, 32, PoolAffinity)
+			benchmarkContention(b, goroutines, pool.Generate)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package idgen
+
+// Epochs used by well-known Snowflake schemes, in milliseconds since Unix epoch
+const (
+	// TwitterEpoch is the Twitter Snowflake epoch (2010-11-04T01:42:54.657Z)
+	TwitterEpoch int64 = 1288834974657
+
+	// DiscordEpoch is the Discord Snowflake epoch (2015-01-01T00:00:00Z)
+	DiscordEpoch int64 = 1420070400000
+
+	// InstagramEpoch is the epoch of Instagram's sharded IDs (2011-08-24T21:07:01.721Z)
+	InstagramEpoch int64 = 1314220021721
+
+	// MastodonEpoch is the Unix epoch: Mastodon IDs store Unix milliseconds directly
+	MastodonEpoch int64 = 0
+)
+
+// Layouts used by well-known Snowflake schemes.
+// Use them with NewWithLayout to generate compatible IDs, or with DecodeSnowflake
+// to inspect IDs received from those platforms.
+//
+// Discord, Instagram and Mastodon use all 64 bits. IDs are int64, so generators stop
+// when the top timestamp bit would be set; see Layout.Capacity for the exact dates.
+//
+// Example:
+//
+//	parts, err := idgen.DecodeSnowflake(175928847299117063, idgen.DiscordLayout, idgen.DiscordEpoch)
+//	fmt.Println(parts.Time()) // 2016-04-30 11:18:25.796 +0000 UTC
+var (
+	// TwitterLayout is the original Twitter Snowflake layout:
+	// 41 bits timestamp, 5 bits datacenter ID, 5 bits worker ID and 12 bits sequence.
+	// The datacenter ID maps to ProcessID.
+	TwitterLayout = Layout{TimestampBits: 41, ProcessIDBits: 5, WorkerIDBits: 5, SequenceBits: 12}
+
+	// DiscordLayout is the Discord Snowflake layout:
+	// 42 bits timestamp, 5 bits internal worker ID, 5 bits internal process ID and 12 bits increment.
+	// Discord places the worker ID above the process ID, so Discord's worker ID maps to
+	// ProcessID and Discord's process ID maps to WorkerID.
+	DiscordLayout = Layout{TimestampBits: 42, ProcessIDBits: 5, WorkerIDBits: 5, SequenceBits: 12}
+
+	// InstagramLayout is the layout of Instagram's sharded IDs:
+	// 41 bits timestamp, 13 bits logical shard ID and 10 bits sequence.
+	// The shard ID maps to ProcessID; there is no worker ID.
+	InstagramLayout = Layout{TimestampBits: 41, ProcessIDBits: 13, WorkerIDBits: 0, SequenceBits: 10}
+
+	// MastodonLayout is the Mastodon ID layout:
+	// 48 bits Unix timestamp followed by 16 bits of sequence data.
+	// There are no process or worker IDs.
+	MastodonLayout = Layout{TimestampBits: 48, ProcessIDBits: 0, WorkerIDBits: 0, SequenceBits: 16}
+)
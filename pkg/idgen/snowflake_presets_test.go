@@ -0,0 +1,182 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLayoutPresetVectors(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     int64
+		layout Layout
+		epoch  int64
+		want   SnowflakeParts
+	}{
+		{
+			// Sample ID from the Discord API reference
+			name:   "discord",
+			id:     175928847299117063,
+			layout: DiscordLayout,
+			epoch:  DiscordEpoch,
+			want:   SnowflakeParts{Timestamp: 1462015105796, ProcessID: 1, WorkerID: 0, Sequence: 7},
+		},
+		{
+			// Tweet created at Wed Oct 10 20:19:24 +0000 2018, from the Twitter API reference
+			name:   "twitter",
+			id:     1050118621198921728,
+			layout: TwitterLayout,
+			epoch:  TwitterEpoch,
+			want:   SnowflakeParts{Timestamp: 1539202764211, ProcessID: 10, WorkerID: 27, Sequence: 0},
+		},
+		{
+			// Worked example from the Instagram engineering blog:
+			// 1387263000 ms since the epoch, shard 1341, sequence 5001 % 1024 = 905
+			name:   "instagram",
+			id:     11637205501278089,
+			layout: InstagramLayout,
+			epoch:  0,
+			want:   SnowflakeParts{Timestamp: 1387263000, ProcessID: 1341, WorkerID: 0, Sequence: 905},
+		},
+		{
+			// Status created at 2019-12-08T03:48:33.901Z, from the Mastodon API reference
+			name:   "mastodon",
+			id:     103270115826048975,
+			layout: MastodonLayout,
+			epoch:  MastodonEpoch,
+			want:   SnowflakeParts{Timestamp: 1575776913849, ProcessID: 0, WorkerID: 0, Sequence: 40911},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.layout.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			parts, err := DecodeSnowflake(tt.id, tt.layout, tt.epoch)
+			if err != nil {
+				t.Fatalf("DecodeSnowflake() error = %v", err)
+			}
+			if parts != tt.want {
+				t.Errorf("DecodeSnowflake() = %+v, want %+v", parts, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWithLayout(t *testing.T) {
+	tests := []struct {
+		name        string
+		processID   int64
+		workerID    int64
+		layout      Layout
+		expectError error
+	}{
+		{"discord", 31, 31, DiscordLayout, nil},
+		{"instagram shard", 8191, 0, InstagramLayout, nil},
+		{"instagram shard too large", 8192, 0, InstagramLayout, ErrInvalidProcessID},
+		{"instagram has no worker", 0, 1, InstagramLayout, ErrInvalidWorkerID},
+		{"mastodon", 0, 0, MastodonLayout, nil},
+		{"invalid layout", 0, 0, Layout{}, ErrInvalidLayout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWithLayout(tt.processID, tt.workerID, tt.layout, 0)
+			if tt.expectError == nil && err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tt.expectError != nil && !errors.Is(err, tt.expectError) {
+				t.Fatalf("Expected error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestNewWithLayoutGenerate(t *testing.T) {
+	tests := []struct {
+		name      string
+		processID int64
+		workerID  int64
+		layout    Layout
+		epoch     int64
+	}{
+		{"twitter", 3, 7, TwitterLayout, TwitterEpoch},
+		{"discord", 1, 0, DiscordLayout, DiscordEpoch},
+		{"instagram", 1341, 0, InstagramLayout, InstagramEpoch},
+		{"mastodon", 0, 0, MastodonLayout, MastodonEpoch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, err := NewWithLayout(tt.processID, tt.workerID, tt.layout, tt.epoch)
+			if err != nil {
+				t.Fatalf("NewWithLayout() error = %v", err)
+			}
+			if generator.Layout() != tt.layout {
+				t.Errorf("Layout() = %+v, want %+v", generator.Layout(), tt.layout)
+			}
+
+			before := time.Now().UnixMilli()
+			id := generator.Generate()
+			after := time.Now().UnixMilli()
+
+			parts, err := DecodeSnowflake(id, tt.layout, tt.epoch)
+			if err != nil {
+				t.Fatalf("DecodeSnowflake() error = %v", err)
+			}
+			if parts.Timestamp < before || parts.Timestamp > after {
+				t.Errorf("Timestamp %d outside [%d, %d]", parts.Timestamp, before, after)
+			}
+			if parts.ProcessID != tt.processID || parts.WorkerID != tt.workerID {
+				t.Errorf("Decoded node %d/%d, want %d/%d", parts.ProcessID, parts.WorkerID, tt.processID, tt.workerID)
+			}
+			if got := generator.ExtractProcessID(id); got != tt.processID {
+				t.Errorf("ExtractProcessID() = %d, want %d", got, tt.processID)
+			}
+			if got := generator.ExtractTimestamp(id); got != parts.Timestamp {
+				t.Errorf("ExtractTimestamp() = %d, want %d", got, parts.Timestamp)
+			}
+		})
+	}
+}
+
+func TestNewWithLayoutReserveRange(t *testing.T) {
+	generator, _ := NewWithLayout(5, 0, InstagramLayout, InstagramEpoch)
+
+	// A 10-bit sequence holds 1024 IDs per millisecond, so the range spans several milliseconds
+	const count = 2500
+	r := generator.ReserveRange(count)
+	ids := r.AppendTo(nil)
+	if len(ids) != count {
+		t.Fatalf("Expected %d IDs, got %d", count, len(ids))
+	}
+
+	for i, id := range ids {
+		if i > 0 && id <= ids[i-1] {
+			t.Fatalf("Range not strictly increasing at index %d", i)
+		}
+		if got := r.At(i); got != id {
+			t.Fatalf("At(%d) = %d, want %d", i, got, id)
+		}
+		if got := generator.ExtractProcessID(id); got != 5 {
+			t.Fatalf("ID %d: shard = %d, want 5", i, got)
+		}
+	}
+
+	wrapped := false
+	for _, id := range ids {
+		if generator.ExtractSequence(id) == InstagramLayout.MaxSequence() {
+			wrapped = true
+		}
+	}
+	if !wrapped {
+		t.Errorf("Expected the sequence to reach %d and wrap", InstagramLayout.MaxSequence())
+	}
+
+	// Generate continues after the reserved range
+	if next := generator.Generate(); next <= ids[count-1] {
+		t.Errorf("Generate() = %d, not after range end %d", next, ids[count-1])
+	}
+}
@@ -0,0 +1,132 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrStateStore is wrapped by the errors returned when a StateStore fails to load or save
+var ErrStateStore = errors.New("snowflake state store failed")
+
+// StateHorizon is how far ahead of the current timestamp a Snowflake generator
+// saves its state. The store is written at most once per horizon, and a
+// restarted generator waits until its clock passes the saved timestamp.
+const StateHorizon = time.Second
+
+// StateStore persists the last timestamp of a Snowflake generator, see WithStateStore.
+//
+// The generator does not save every ID: when its timestamp passes the saved one,
+// it saves a timestamp StateHorizon ahead and uses that as the new bound.
+// On startup it loads the bound and issues no ID at or before it, so IDs stay
+// unique even if the process crashed and the clock was set back.
+type StateStore interface {
+	// Load returns the saved timestamp in Unix milliseconds, or 0 if none was saved
+	Load(ctx context.Context) (int64, error)
+
+	// Save stores the timestamp in Unix milliseconds. It is called with the generator locked.
+	Save(ctx context.Context, timestamp int64) error
+}
+
+// FileStateStore is a StateStore that keeps the timestamp in a file.
+// Save writes a uniquely named temporary file in the same directory, syncs it,
+// renames it into place and syncs the directory, so neither a crash nor a power
+// loss leaves a partial value. An empty or corrupt file makes Load fail
+// rather than silently restart from an older timestamp.
+//
+// Example:
+//
+//	generator, err := idgen.NewSnowflakeGenerator(
+//	    idgen.WithNode(5, 12),
+//	    idgen.WithStateStore(idgen.FileStateStore{Path: "/var/lib/app/snowflake.state"}),
+//	)
+type FileStateStore struct {
+	Path string
+}
+
+// Load reads the timestamp from the file; a missing file yields 0
+func (f FileStateStore) Load(ctx context.Context) (int64, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	// Save terminates the value with a newline, so a missing one means a truncated write
+	text, complete := strings.CutSuffix(string(data), "\n")
+	if !complete {
+		return 0, fmt.Errorf("%s: truncated state %q", f.Path, data)
+	}
+	timestamp, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: corrupt state %q: %w", f.Path, data, err)
+	}
+	return timestamp, nil
+}
+
+// Save durably writes the timestamp to the file
+func (f FileStateStore) Save(ctx context.Context, timestamp int64) error {
+	dir, name := filepath.Split(f.Path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Removing fails harmlessly once the file has been renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.FormatInt(timestamp, 10) + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), f.Path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir flushes a directory entry, so a rename in it survives a power loss.
+// Windows cannot sync directories and makes renames durable on its own.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// persist saves a new horizon to the state store once timestamp passes the
+// saved one. Must be called with s.mu held.
+func (s *Snowflake) persist(ctx context.Context, timestamp int64) error {
+	if s.store == nil || timestamp <= s.persistedUntil {
+		return nil
+	}
+	horizon := timestamp + StateHorizon.Milliseconds()
+	if err := s.store.Save(ctx, horizon); err != nil {
+		return fmt.Errorf("%w: %w", ErrStateStore, err)
+	}
+	s.persistedUntil = horizon
+	return nil
+}
@@ -1,7 +1,11 @@
 package idgen
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -290,6 +294,343 @@ func TestSnowflakeMultipleMachines(t *testing.T) {
 	}
 }
 
+func TestSnowflakeReserveRange(t *testing.T) {
+	generator, err := New(6, 9)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	before := generator.Generate()
+
+	// Spans several milliseconds worth of sequence numbers
+	r := generator.ReserveRange(10000)
+	if r.Len() != 10000 {
+		t.Fatalf("Expected range of 10000 IDs, got %d", r.Len())
+	}
+
+	ids := r.AppendTo(nil)
+	if len(ids) != 10000 {
+		t.Fatalf("Expected 10000 IDs from AppendTo, got %d", len(ids))
+	}
+	if ids[0] <= before {
+		t.Errorf("Range start %d not after previously generated ID %d", ids[0], before)
+	}
+	for i, id := range ids {
+		if i > 0 && id <= ids[i-1] {
+			t.Fatalf("Range not strictly increasing at index %d: %d <= %d", i, id, ids[i-1])
+		}
+		if r.At(i) != id {
+			t.Fatalf("At(%d) = %d, want %d", i, r.At(i), id)
+		}
+		if generator.ExtractProcessID(id) != 6 || generator.ExtractWorkerID(id) != 9 {
+			t.Fatalf("ID %d has wrong node bits", id)
+		}
+	}
+
+	after := generator.Generate()
+	if after <= ids[len(ids)-1] {
+		t.Errorf("ID generated after range %d not greater than range end %d", after, ids[len(ids)-1])
+	}
+}
+
+func TestIDRangeCrossesMillisecond(t *testing.T) {
+	start := int64(100)<<timestampShift | int64(maxSequence-1)
+	r := IDRange{Start: start, Count: 3}
+
+	want := []int64{
+		start,
+		int64(100)<<timestampShift | int64(maxSequence),
+		int64(101) << timestampShift,
+	}
+
+	dst := make([]int64, 5)
+	if n := r.Fill(dst); n != 3 {
+		t.Fatalf("Fill() = %d, want 3", n)
+	}
+	for i, id := range want {
+		if dst[i] != id {
+			t.Errorf("Fill()[%d] = %d, want %d", i, dst[i], id)
+		}
+		if r.At(i) != id {
+			t.Errorf("At(%d) = %d, want %d", i, r.At(i), id)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, ok := r.Next(); !ok {
+			t.Fatalf("Next() exhausted after %d IDs", i)
+		}
+	}
+	if _, ok := r.Next(); ok {
+		t.Error("Next() on empty range returned ok")
+	}
+}
+
+func TestSnowflakeReserveRangeEmpty(t *testing.T) {
+	generator, _ := New(1, 1)
+	if r := generator.ReserveRange(0); r.Len() != 0 {
+		t.Errorf("Expected empty range, got %d IDs", r.Len())
+	}
+}
+
+func TestSnowflakeGenerateBatchIntoAllocations(t *testing.T) {
+	generator, _ := New(2, 2)
+	buf := make([]int64, 1000)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		generator.GenerateBatchInto(buf)
+	})
+	if allocs != 0 {
+		t.Errorf("GenerateBatchInto allocated %.0f times, want 0", allocs)
+	}
+}
+
+func TestSnowflakeGenerateContext(t *testing.T) {
+	generator, _ := New(1, 2)
+
+	id, err := generator.GenerateContext(context.Background())
+	if err != nil {
+		t.Fatalf("GenerateContext() error = %v", err)
+	}
+	if id <= 0 {
+		t.Errorf("Expected positive ID, got %d", id)
+	}
+}
+
+func TestSnowflakeGenerateContextClockBackwards(t *testing.T) {
+	generator, _ := New(1, 2)
+
+	// Pretend the last ID was issued one second in the future
+	generator.lastTimestamp = time.Now().UnixMilli() + 1000
+	generator.sequence = 7
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := generator.GenerateContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	var waitErr *WaitCanceledError
+	if !errors.As(err, &waitErr) || waitErr.Reason != reasonClockBackwards {
+		t.Errorf("Expected *WaitCanceledError for clock regression, got %#v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GenerateContext blocked for %v past its deadline", elapsed)
+	}
+	if generator.sequence != 7 {
+		t.Errorf("Canceled call modified generator state: sequence = %d", generator.sequence)
+	}
+}
+
+func TestSnowflakeClockPolicyFail(t *testing.T) {
+	generator, _ := New(1, 2)
+	generator.SetClockPolicy(ClockFail)
+	if generator.ClockPolicy() != ClockFail {
+		t.Fatalf("ClockPolicy() = %v, want %v", generator.ClockPolicy(), ClockFail)
+	}
+
+	// Pretend the clock was one second ahead when the last ID was issued
+	future := time.Now().UnixMilli() + 1000
+	generator.lastTimestamp = future
+	generator.lastClock = future
+
+	start := time.Now()
+	if _, err := generator.GenerateContext(context.Background()); !errors.Is(err, ErrClockMovedBackwards) {
+		t.Fatalf("Expected ErrClockMovedBackwards, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("ClockFail waited %v", elapsed)
+	}
+
+	// A reserved range ending in the future is not a regression, so the generator still waits
+	generator, _ = New(1, 2)
+	generator.SetClockPolicy(ClockFail)
+	generator.ReserveRange(2 * 4096)
+	if _, err := generator.GenerateContext(context.Background()); err != nil {
+		t.Errorf("GenerateContext() after ReserveRange error = %v", err)
+	}
+}
+
+// offsetClock runs behind the system clock by an adjustable offset
+type offsetClock struct {
+	offset atomic.Int64
+}
+
+func (c *offsetClock) Now() time.Time {
+	return time.Now().Add(-time.Duration(c.offset.Load()))
+}
+
+// stepBack moves the clock d backwards
+func (c *offsetClock) stepBack(d time.Duration) {
+	c.offset.Add(int64(d))
+}
+
+func TestSnowflakeGenerateWaitsUnderClockFail(t *testing.T) {
+	clock := &offsetClock{}
+	generator, err := NewSnowflakeGenerator(WithNode(1, 2), WithClock(clock), WithClockPolicy(ClockFail))
+	if err != nil {
+		t.Fatalf("NewSnowflakeGenerator() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		generate func() int64
+	}{
+		{"Generate", generator.Generate},
+		{"GenerateID", func() int64 { return int64(generator.GenerateID()) }},
+		{"GenerateBatch", func() int64 { return generator.GenerateBatch(3)[2] }},
+		{"ReserveRange", func() int64 { return generator.ReserveRange(3).Start }},
+	}
+
+	last := generator.Generate()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.stepBack(20 * time.Millisecond)
+			id := tt.generate()
+			if id <= last {
+				t.Errorf("%s() after regression = %d, want > %d", tt.name, id, last)
+			}
+			last = id
+		})
+	}
+
+	// The error-returning methods apply the policy
+	failing := map[string]func() error{
+		"GenerateContext": func() error { _, err := generator.GenerateContext(context.Background()); return err },
+		"GenerateString":  func() error { _, err := generator.GenerateString(context.Background()); return err },
+		"GenerateBatchE":  func() error { _, err := generator.GenerateBatchE(3); return err },
+		"ReserveRangeContext": func() error {
+			_, err := generator.ReserveRangeContext(context.Background(), 3)
+			return err
+		},
+	}
+	for name, generate := range failing {
+		clock.stepBack(20 * time.Millisecond)
+		if err := generate(); !errors.Is(err, ErrClockMovedBackwards) {
+			t.Errorf("%s() error = %v, want ErrClockMovedBackwards", name, err)
+		}
+	}
+}
+
+func TestSnowflakeGenerateContextCanceled(t *testing.T) {
+	generator, _ := New(1, 2)
+	generator.lastTimestamp = time.Now().UnixMilli() + 1000
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := generator.GenerateContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestAtomicSnowflakeGenerateContextCanceled(t *testing.T) {
+	generator, _ := NewAtomic(1, 2)
+
+	// Exhaust the sequence of a millisecond one second in the future
+	elapsed := time.Now().UnixMilli() - generator.Epoch() + 1000
+	generator.state.Store(uint64(elapsed)<<sequenceBits | maxSequence)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := generator.GenerateContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	var waitErr *WaitCanceledError
+	if !errors.As(err, &waitErr) || waitErr.Reason != reasonSequenceExhausted {
+		t.Errorf("Expected *WaitCanceledError for sequence exhaustion, got %#v", err)
+	}
+}
+
+func TestAtomicSnowflakeLayout(t *testing.T) {
+	atomicGen, err := NewAtomic(10, 20)
+	if err != nil {
+		t.Fatalf("Failed to create atomic generator: %v", err)
+	}
+	decoder, _ := New(10, 20)
+
+	before := time.Now().UnixMilli()
+	id := atomicGen.Generate()
+	after := time.Now().UnixMilli()
+
+	if id <= 0 {
+		t.Fatalf("Expected positive ID, got %d", id)
+	}
+	if decoder.ExtractProcessID(id) != 10 {
+		t.Errorf("Expected processID 10, got %d", decoder.ExtractProcessID(id))
+	}
+	if decoder.ExtractWorkerID(id) != 20 {
+		t.Errorf("Expected workerID 20, got %d", decoder.ExtractWorkerID(id))
+	}
+	if ts := decoder.ExtractTimestamp(id); ts < before || ts > after {
+		t.Errorf("Timestamp %d not between %d and %d", ts, before, after)
+	}
+	if atomicGen.ExtractTimestamp(id) != decoder.ExtractTimestamp(id) {
+		t.Errorf("Atomic and mutex generators decode timestamps differently")
+	}
+}
+
+func TestAtomicSnowflakeInvalidIDs(t *testing.T) {
+	if _, err := NewAtomic(32, 0); err != ErrInvalidProcessID {
+		t.Errorf("Expected ErrInvalidProcessID, got %v", err)
+	}
+	if _, err := NewAtomic(0, -1); err != ErrInvalidWorkerID {
+		t.Errorf("Expected ErrInvalidWorkerID, got %v", err)
+	}
+}
+
+func TestAtomicSnowflakeMonotonic(t *testing.T) {
+	generator, _ := NewAtomic(1, 1)
+
+	// Crosses several sequence rollovers
+	ids := generator.GenerateBatch(10000)
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("IDs not strictly increasing at index %d: %d <= %d", i, ids[i], ids[i-1])
+		}
+	}
+}
+
+func TestAtomicSnowflakeConcurrency(t *testing.T) {
+	generator, _ := NewAtomic(3, 4)
+
+	const goroutines = 64
+	const idsPerGoroutine = 500
+
+	var wg sync.WaitGroup
+	results := make([][]int64, goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids := make([]int64, idsPerGoroutine)
+			for j := range ids {
+				ids[j] = generator.Generate()
+			}
+			results[i] = ids
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[int64]bool, goroutines*idsPerGoroutine)
+	for _, ids := range results {
+		for j, id := range ids {
+			if seen[id] {
+				t.Fatalf("Duplicate ID in concurrent generation: %d", id)
+			}
+			seen[id] = true
+			if j > 0 && id <= ids[j-1] {
+				t.Errorf("IDs not increasing within a goroutine: %d <= %d", id, ids[j-1])
+			}
+		}
+	}
+}
+
 // Benchmarks
 func BenchmarkSnowflakeGenerate(b *testing.B) {
 	generator, _ := New(1, 2)
@@ -316,3 +657,86 @@ func BenchmarkSnowflakeBatch(b *testing.B) {
 		generator.GenerateBatch(100)
 	}
 }
+
+func BenchmarkSnowflakeBatchInto(b *testing.B) {
+	generator, _ := New(2, 5)
+	buf := make([]int64, 100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		generator.GenerateBatchInto(buf)
+	}
+}
+
+// benchmarkContention spreads b.N calls to generate across a fixed number of goroutines
+func benchmarkContention(b *testing.B, goroutines int, generate func() int64) {
+	var wg sync.WaitGroup
+	perGoroutine := b.N / goroutines
+	remainder := b.N % goroutines
+
+	b.ResetTimer()
+	for g := 0; g < goroutines; g++ {
+		n := perGoroutine
+		if g < remainder {
+			n++
+		}
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				generate()
+			}
+		}(n)
+	}
+	wg.Wait()
+}
+
+func BenchmarkSnowflakeContention(b *testing.B) {
+	for _, goroutines := range []int{1, 8, 64, 256} {
+		b.Run(fmt.Sprintf("mutex/goroutines=%d", goroutines), func(b *testing.B) {
+			generator, _ := New(1, 5)
+			benchmarkContention(b, goroutines, generator.Generate)
+		})
+		b.Run(fmt.Sprintf("atomic/goroutines=%d", goroutines), func(b *testing.B) {
+			generator, _ := NewAtomic(1, 5)
+			benchmarkContention(b, goroutines, generator.Generate)
+		})
+	}
+}
+
+func TestSnowflakeGenerateBatchE(t *testing.T) {
+	generator, _ := New(1, 2)
+
+	ids, err := generator.GenerateBatchE(5000)
+	if err != nil {
+		t.Fatalf("GenerateBatchE() error = %v", err)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("IDs not strictly increasing at %d: %d <= %d", i, ids[i], ids[i-1])
+		}
+	}
+
+	if _, err := generator.GenerateBatchE(-1); err == nil {
+		t.Error("GenerateBatchE(-1) expected error")
+	}
+}
+
+func TestSnowflakeGenerateBatchNotAhead(t *testing.T) {
+	generator, _ := New(1, 2)
+
+	// 100000 IDs need about 25 milliseconds of sequence numbers
+	ids := generator.GenerateBatch(100000)
+	for _, id := range []int64{ids[0], ids[len(ids)-1]} {
+		if err := ValidateSnowflake(id, DefaultLayout, DefaultEpoch); err != nil {
+			t.Fatalf("ValidateSnowflake(%d) error = %v", id, err)
+		}
+	}
+
+	// Generate does not stall behind a batch
+	start := time.Now()
+	generator.Generate()
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Generate() after GenerateBatch waited %v", elapsed)
+	}
+}
@@ -0,0 +1,77 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Snowflake to UUIDv7 mapping
+//
+// UUIDv7FromSnowflake maps an ID with DefaultLayout to a UUIDv7 deterministically:
+//
+//	[48 bits Unix ms] [4 bits version 7] [12 bits rand_a] [2 bits variant] [62 bits rand_b]
+//
+// The millisecond timestamp is kept, and the 22 low bits of the Snowflake ID
+// (process ID, worker ID and sequence) fill rand_a and the top 10 bits of rand_b.
+// The remaining 52 bits are zero. Mapped UUIDs therefore sort exactly like the
+// Snowflake IDs they come from, and SnowflakeFromUUIDv7 recovers the original ID.
+
+// UUIDv7FromSnowflake converts a Snowflake ID generated with DefaultLayout into a UUIDv7
+// that keeps its millisecond timestamp and sort order.
+//
+// Parameters:
+//   - id: A Snowflake ID generated with DefaultLayout
+//   - epoch: Epoch in milliseconds since Unix epoch used by the generator
+//
+// Returns:
+//   - UUID: The mapped UUIDv7
+//   - error: ErrSnowflakeNegative if id is negative, or ErrTimeOutOfRange if
+//     the timestamp is before 1970 or does not fit in 48 bits
+//
+// Example:
+//
+//	uuid, err := idgen.UUIDv7FromSnowflake(order.ID, idgen.DefaultEpoch)
+//	// order.ID == idgen.SnowflakeFromUUIDv7(uuid, idgen.DefaultEpoch)
+func UUIDv7FromSnowflake(id int64, epoch int64) (UUID, error) {
+	if id < 0 {
+		return UUID{}, ErrSnowflakeNegative
+	}
+
+	ms := (id >> timestampShift) + epoch
+	if ms < 0 || ms > maxTimestamp48 {
+		return UUID{}, fmt.Errorf("%w: timestamp %d ms does not fit in 48 bits", ErrTimeOutOfRange, ms)
+	}
+
+	node := uint64(id) & (1<<timestampShift - 1)
+	var uuid UUID
+	binary.BigEndian.PutUint64(uuid[0:8], uint64(ms)<<16|0x7000|node>>10)
+	binary.BigEndian.PutUint64(uuid[8:16], 0x8000000000000000|(node&0x3ff)<<52)
+	return uuid, nil
+}
+
+// SnowflakeFromUUIDv7 converts a UUIDv7 produced by UUIDv7FromSnowflake back into the
+// original Snowflake ID.
+//
+// Parameters:
+//   - uuid: A UUID produced by UUIDv7FromSnowflake
+//   - epoch: The epoch passed to UUIDv7FromSnowflake
+//
+// Returns:
+//   - int64: The original Snowflake ID
+//   - error: ErrInvalidUUID if uuid was not produced by UUIDv7FromSnowflake, or
+//     ErrTimeOutOfRange if its timestamp is before epoch or past the DefaultLayout lifetime
+func SnowflakeFromUUIDv7(uuid UUID, epoch int64) (int64, error) {
+	hi := binary.BigEndian.Uint64(uuid[0:8])
+	lo := binary.BigEndian.Uint64(uuid[8:16])
+	if hi&0xf000 != 0x7000 || lo>>62 != 0b10 || lo&(1<<52-1) != 0 {
+		return 0, fmt.Errorf("%w: %s was not converted from a Snowflake ID", ErrInvalidUUID, uuid)
+	}
+
+	elapsed := int64(hi>>16) - epoch
+	if elapsed < 0 || elapsed > DefaultLayout.timestampLimit() {
+		return 0, fmt.Errorf("%w: timestamp %d ms is outside the Snowflake lifetime", ErrTimeOutOfRange, int64(hi>>16))
+	}
+
+	node := (hi&0x0fff)<<10 | (lo>>52)&0x3ff
+	return elapsed<<timestampShift | int64(node), nil
+}
@@ -0,0 +1,130 @@
+package idgen
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestUUIDv7FromSnowflake(t *testing.T) {
+	generator, _ := NewWithEpoch(31, 17, DefaultEpoch)
+	ids := generator.GenerateBatch(5000)
+	ids = append(ids, 0, 1<<22-1, 1<<41<<22-1)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var prev UUID
+	for i, id := range ids {
+		uuid, err := UUIDv7FromSnowflake(id, DefaultEpoch)
+		if err != nil {
+			t.Fatalf("UUIDv7FromSnowflake(%d) error = %v", id, err)
+		}
+		if uuid[6]>>4 != 7 || uuid[8]>>6 != 0b10 {
+			t.Fatalf("UUIDv7FromSnowflake(%d) = %s is not a UUIDv7", id, uuid)
+		}
+		if got, want := ExtractTimestampFromUUIDv7(uuid), (id>>timestampShift)+DefaultEpoch; got != want {
+			t.Fatalf("Timestamp = %d, want %d", got, want)
+		}
+		if i > 0 && bytes.Compare(prev[:], uuid[:]) >= 0 {
+			t.Fatalf("Order not preserved at index %d: %s >= %s", i, prev, uuid)
+		}
+		prev = uuid
+
+		back, err := SnowflakeFromUUIDv7(uuid, DefaultEpoch)
+		if err != nil || back != id {
+			t.Fatalf("SnowflakeFromUUIDv7(%s) = %d, %v, want %d", uuid, back, err, id)
+		}
+	}
+}
+
+func TestUUIDv7FromSnowflakeErrors(t *testing.T) {
+	if _, err := UUIDv7FromSnowflake(-1, DefaultEpoch); !errors.Is(err, ErrSnowflakeNegative) {
+		t.Errorf("Expected ErrSnowflakeNegative, got %v", err)
+	}
+	if _, err := UUIDv7FromSnowflake(0, -1); !errors.Is(err, ErrTimeOutOfRange) {
+		t.Errorf("Expected ErrTimeOutOfRange before 1970, got %v", err)
+	}
+	if _, err := UUIDv7FromSnowflake(1<<62, 1<<48); !errors.Is(err, ErrTimeOutOfRange) {
+		t.Errorf("Expected ErrTimeOutOfRange past 48 bits, got %v", err)
+	}
+
+	random, _ := NewUUIDv7()
+	random[15] |= 1
+	if _, err := SnowflakeFromUUIDv7(random, DefaultEpoch); !errors.Is(err, ErrInvalidUUID) {
+		t.Errorf("Expected ErrInvalidUUID for a random UUIDv7, got %v", err)
+	}
+
+	v4, _ := NewUUIDv4()
+	if _, err := SnowflakeFromUUIDv7(v4, DefaultEpoch); !errors.Is(err, ErrInvalidUUID) {
+		t.Errorf("Expected ErrInvalidUUID for a UUIDv4, got %v", err)
+	}
+
+	uuid, _ := UUIDv7FromSnowflake(0, DefaultEpoch)
+	if _, err := SnowflakeFromUUIDv7(uuid, DefaultEpoch+1); !errors.Is(err, ErrTimeOutOfRange) {
+		t.Errorf("Expected ErrTimeOutOfRange before the epoch, got %v", err)
+	}
+}
+
+func TestULIDUUIDConversion(t *testing.T) {
+	uuid, _ := NewUUIDv7()
+	ulid := ULIDFromUUID(uuid)
+	if ulid.UUID() != uuid {
+		t.Fatalf("ULID round trip = %s, want %s", ulid.UUID(), uuid)
+	}
+	if ulid.Timestamp() != ExtractTimestampFromUUIDv7(uuid) {
+		t.Errorf("Timestamp = %d, want %d", ulid.Timestamp(), ExtractTimestampFromUUIDv7(uuid))
+	}
+	if d := time.Since(ulid.Time()); d < 0 || d > time.Minute {
+		t.Errorf("Time() = %v is not recent", ulid.Time())
+	}
+
+	s := ulid.String()
+	if len(s) != 26 {
+		t.Fatalf("String() = %q, want 26 characters", s)
+	}
+	parsed, err := ParseULID(s)
+	if err != nil || parsed != ulid {
+		t.Fatalf("ParseULID(%q) = %v, %v", s, parsed, err)
+	}
+}
+
+func TestParseULID(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"canonical", "01AN4Z07BY79KA1307SR9X4MV3", "01AN4Z07BY79KA1307SR9X4MV3", false},
+		{"lowercase", "01an4z07by79ka1307sr9x4mv3", "01AN4Z07BY79KA1307SR9X4MV3", false},
+		{"aliases", "O1AN4Z07BY79KAI3O7SR9X4MV3", "01AN4Z07BY79KA1307SR9X4MV3", false},
+		{"max", "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", false},
+		{"overflow", "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "", true},
+		{"too short", "01AN4Z07BY79KA1307SR9X4MV", "", true},
+		{"invalid character", "01AN4Z07BY79KA1307SR9X4MVU", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := ParseULID(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidULID) {
+					t.Errorf("Expected ErrInvalidULID, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseULID() error = %v", err)
+			}
+			if u.String() != tt.want {
+				t.Errorf("String() = %q, want %q", u.String(), tt.want)
+			}
+
+			var text ULID
+			if err := text.UnmarshalText([]byte(tt.input)); err != nil || text != u {
+				t.Errorf("UnmarshalText() = %v, %v", text, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package idgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sqids errors
+var (
+	// ErrInvalidSqidsAlphabet is returned when a Sqids alphabet is too short,
+	// has repeated characters or non-ASCII characters
+	ErrInvalidSqidsAlphabet = errors.New("invalid Sqids alphabet")
+
+	// ErrInvalidSqidsMinLength is returned when the Sqids minimum length is out of range
+	ErrInvalidSqidsMinLength = errors.New("invalid Sqids minimum length")
+
+	// ErrSqidsBlocked is returned when no ID free of blocked words could be generated
+	ErrSqidsBlocked = errors.New("reached max attempts to generate a Sqids ID free of blocked words")
+)
+
+// DefaultSqidsAlphabet is the default Sqids alphabet
+const DefaultSqidsAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// MaxSqidsMinLength is the largest supported Sqids minimum length
+const MaxSqidsMinLength = 255
+
+// SqidsOptions configures a Sqids encoder
+type SqidsOptions struct {
+	// Alphabet is the set of characters used in IDs; DefaultSqidsAlphabet when empty.
+	// It must contain at least 3 unique ASCII characters.
+	Alphabet string
+
+	// MinLength pads IDs to at least this many characters (0-255)
+	MinLength int
+
+	// Blocklist lists words that must not appear in IDs, case-insensitively.
+	// DefaultSqidsBlocklist is used when nil; pass an empty slice to disable blocking.
+	Blocklist []string
+}
+
+// Sqids encodes one or more non-negative integers into short, URL-safe strings
+// that do not look sequential, following the Sqids specification (https://sqids.org).
+//
+// Sqids is an encoding, not encryption: anyone who knows the alphabet can decode IDs.
+// Use an Obfuscator when the numbers themselves must stay hidden.
+//
+// A Sqids encoder is immutable and safe for concurrent use.
+type Sqids struct {
+	alphabet  []byte
+	minLength int
+	blocklist []string
+}
+
+// NewSqids creates a Sqids encoder.
+//
+// Parameters:
+//   - opts: Alphabet, minimum length and blocklist; the zero value uses the defaults
+//
+// Returns:
+//   - *Sqids: A new encoder
+//   - error: ErrInvalidSqidsAlphabet or ErrInvalidSqidsMinLength if opts are invalid
+//
+// Example:
+//
+//	sqids, _ := idgen.NewSqids(idgen.SqidsOptions{MinLength: 8})
+//	id, _ := sqids.Encode(1, 2, 3)
+//	numbers := sqids.Decode(id) // [1 2 3]
+func NewSqids(opts SqidsOptions) (*Sqids, error) {
+	alphabet := opts.Alphabet
+	if alphabet == "" {
+		alphabet = DefaultSqidsAlphabet
+	}
+
+	if len(alphabet) < 3 {
+		return nil, fmt.Errorf("%w: must contain at least 3 characters", ErrInvalidSqidsAlphabet)
+	}
+	var seen [256]bool
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c >= 0x80 {
+			return nil, fmt.Errorf("%w: must contain only ASCII characters", ErrInvalidSqidsAlphabet)
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("%w: %q is repeated", ErrInvalidSqidsAlphabet, c)
+		}
+		seen[c] = true
+	}
+
+	if opts.MinLength < 0 || opts.MinLength > MaxSqidsMinLength {
+		return nil, fmt.Errorf("%w: must be between 0 and %d", ErrInvalidSqidsMinLength, MaxSqidsMinLength)
+	}
+
+	words := opts.Blocklist
+	if words == nil {
+		words = DefaultSqidsBlocklist
+	}
+
+	// Only keep words that can actually appear in an ID
+	lowerAlphabet := strings.ToLower(alphabet)
+	blocklist := make([]string, 0, len(words))
+	for _, word := range words {
+		if len(word) < 3 {
+			continue
+		}
+		word = strings.ToLower(word)
+		if strings.Trim(word, lowerAlphabet) == "" {
+			blocklist = append(blocklist, word)
+		}
+	}
+
+	s := &Sqids{
+		alphabet:  []byte(alphabet),
+		minLength: opts.MinLength,
+		blocklist: blocklist,
+	}
+	sqidsShuffle(s.alphabet)
+	return s, nil
+}
+
+// Encode encodes numbers into an ID. Encoding no numbers returns an empty string.
+//
+// Returns:
+//   - string: The ID
+//   - error: ErrSqidsBlocked if every candidate ID contained a blocked word
+func (s *Sqids) Encode(numbers ...uint64) (string, error) {
+	if len(numbers) == 0 {
+		return "", nil
+	}
+	return s.encode(numbers, 0)
+}
+
+// Decode decodes an ID into its numbers.
+// It returns an empty slice if id is empty or contains characters outside the alphabet.
+//
+// Decoding is not strict: several IDs can decode to the same numbers.
+// Compare the result of Encode with the input when a canonical ID is required.
+func (s *Sqids) Decode(id string) []uint64 {
+	numbers := []uint64{}
+	if id == "" {
+		return numbers
+	}
+	for i := 0; i < len(id); i++ {
+		if bytes.IndexByte(s.alphabet, id[i]) < 0 {
+			return numbers
+		}
+	}
+
+	alphabet := make([]byte, len(s.alphabet))
+	offset := bytes.IndexByte(s.alphabet, id[0])
+	rotate(alphabet, s.alphabet, offset)
+	reverse(alphabet)
+
+	rest := id[1:]
+	for rest != "" {
+		separator := alphabet[0]
+		chunk, next, found := strings.Cut(rest, string(separator))
+		if chunk == "" {
+			return numbers
+		}
+		number, ok := sqidsToNumber(chunk, alphabet[1:])
+		if !ok {
+			return []uint64{}
+		}
+		numbers = append(numbers, number)
+		if found {
+			sqidsShuffle(alphabet)
+		}
+		rest = next
+	}
+	return numbers
+}
+
+// encode implements the Sqids encoding, retrying with another offset when the ID is blocked
+func (s *Sqids) encode(numbers []uint64, increment int) (string, error) {
+	size := len(s.alphabet)
+	if increment > size {
+		return "", ErrSqidsBlocked
+	}
+
+	offset := len(numbers)
+	for i, v := range numbers {
+		offset += int(s.alphabet[v%uint64(size)]) + i
+	}
+	offset = (offset%size + increment) % size
+
+	alphabet := make([]byte, size)
+	rotate(alphabet, s.alphabet, offset)
+	prefix := alphabet[0]
+	reverse(alphabet)
+
+	id := make([]byte, 0, 1+len(numbers)*12)
+	id = append(id, prefix)
+	for i, number := range numbers {
+		id = sqidsAppendNumber(id, number, alphabet[1:])
+		if i < len(numbers)-1 {
+			id = append(id, alphabet[0])
+			sqidsShuffle(alphabet)
+		}
+	}
+
+	if len(id) < s.minLength {
+		id = append(id, alphabet[0])
+		for len(id) < s.minLength {
+			sqidsShuffle(alphabet)
+			n := s.minLength - len(id)
+			if n > size {
+				n = size
+			}
+			id = append(id, alphabet[:n]...)
+		}
+	}
+
+	if s.isBlocked(string(id)) {
+		return s.encode(numbers, increment+1)
+	}
+	return string(id), nil
+}
+
+// isBlocked reports whether id contains a blocked word
+func (s *Sqids) isBlocked(id string) bool {
+	id = strings.ToLower(id)
+	for _, word := range s.blocklist {
+		if len(word) > len(id) {
+			continue
+		}
+		switch {
+		case len(id) <= 3 || len(word) <= 3:
+			if id == word {
+				return true
+			}
+		case strings.ContainsAny(word, "0123456789"):
+			if strings.HasPrefix(id, word) || strings.HasSuffix(id, word) {
+				return true
+			}
+		case strings.Contains(id, word):
+			return true
+		}
+	}
+	return false
+}
+
+// FormatSnowflakeID implements SnowflakeIDCodec.
+func (s *Sqids) FormatSnowflakeID(id SnowflakeID) (string, error) {
+	if id < 0 {
+		return "", fmt.Errorf("%w: %w", ErrInvalidSnowflakeID, ErrSnowflakeNegative)
+	}
+	return s.Encode(uint64(id))
+}
+
+// ParseSnowflakeID implements SnowflakeIDCodec.
+// Only the canonical ID of a single number is accepted, so every Snowflake ID
+// has exactly one textual form.
+func (s *Sqids) ParseSnowflakeID(text string) (SnowflakeID, error) {
+	numbers := s.Decode(text)
+	if len(numbers) != 1 || numbers[0] > 1<<63-1 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSnowflakeID, text)
+	}
+	if canonical, err := s.Encode(numbers[0]); err != nil || canonical != text {
+		return 0, fmt.Errorf("%w: %q is not canonical", ErrInvalidSnowflakeID, text)
+	}
+	return SnowflakeID(numbers[0]), nil
+}
+
+// sqidsShuffle deterministically shuffles the alphabet in place
+func sqidsShuffle(alphabet []byte) {
+	size := len(alphabet)
+	for i, j := 0, size-1; j > 0; i, j = i+1, j-1 {
+		r := (i*j + int(alphabet[i]) + int(alphabet[j])) % size
+		alphabet[i], alphabet[r] = alphabet[r], alphabet[i]
+	}
+}
+
+// sqidsAppendNumber appends number written in the given alphabet
+func sqidsAppendNumber(dst []byte, number uint64, alphabet []byte) []byte {
+	var buf [64]byte
+	i := len(buf)
+	size := uint64(len(alphabet))
+	for {
+		i--
+		buf[i] = alphabet[number%size]
+		number /= size
+		if number == 0 {
+			break
+		}
+	}
+	return append(dst, buf[i:]...)
+}
+
+// sqidsToNumber parses a chunk written in the given alphabet, reporting false on overflow
+func sqidsToNumber(chunk string, alphabet []byte) (uint64, bool) {
+	size := uint64(len(alphabet))
+	var number uint64
+	for i := 0; i < len(chunk); i++ {
+		digit := bytes.IndexByte(alphabet, chunk[i])
+		if digit < 0 || number > (^uint64(0)-uint64(digit))/size {
+			return 0, false
+		}
+		number = number*size + uint64(digit)
+	}
+	return number, true
+}
+
+// rotate copies src into dst rotated left by offset
+func rotate(dst, src []byte, offset int) {
+	n := copy(dst, src[offset:])
+	copy(dst[n:], src[:offset])
+}
+
+// reverse reverses b in place
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
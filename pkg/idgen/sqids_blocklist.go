@@ -0,0 +1,50 @@
+package idgen
+
+// DefaultSqidsBlocklist is the default list of words that Sqids IDs must not contain.
+//
+// It is a compact subset of the reference Sqids blocklist covering common English
+// profanity. IDs that would contain a word from the reference list but not from this
+// one can differ from other Sqids implementations; pass the reference list in
+// SqidsOptions.Blocklist when IDs must match them exactly.
+var DefaultSqidsBlocklist = []string{
+	"anal",
+	"anus",
+	"arse",
+	"ass",
+	"bastard",
+	"bitch",
+	"blowjob",
+	"boner",
+	"boob",
+	"butt",
+	"clit",
+	"cock",
+	"cum",
+	"cunt",
+	"dick",
+	"dildo",
+	"dyke",
+	"fag",
+	"fuck",
+	"jizz",
+	"kike",
+	"nazi",
+	"nigga",
+	"nigger",
+	"penis",
+	"piss",
+	"porn",
+	"pussy",
+	"rape",
+	"retard",
+	"scrotum",
+	"sex",
+	"shit",
+	"slut",
+	"spic",
+	"tit",
+	"twat",
+	"vagina",
+	"wank",
+	"whore",
+}
@@ -0,0 +1,207 @@
+package idgen
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSqidsVectors(t *testing.T) {
+	// Vectors from the Sqids specification test suite
+	tests := []struct {
+		numbers []uint64
+		id      string
+	}{
+		{[]uint64{1, 2, 3}, "86Rf07"},
+		{[]uint64{0}, "bM"},
+		{[]uint64{1}, "Uk"},
+		{[]uint64{2}, "gb"},
+		{[]uint64{3}, "Ef"},
+		{[]uint64{0, 0}, "SvIz"},
+		{[]uint64{0, 1}, "n3qa"},
+	}
+
+	s, err := NewSqids(SqidsOptions{})
+	if err != nil {
+		t.Fatalf("NewSqids() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			id, err := s.Encode(tt.numbers...)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if id != tt.id {
+				t.Errorf("Encode(%v) = %q, want %q", tt.numbers, id, tt.id)
+			}
+			if got := s.Decode(tt.id); !reflect.DeepEqual(got, tt.numbers) {
+				t.Errorf("Decode(%q) = %v, want %v", tt.id, got, tt.numbers)
+			}
+		})
+	}
+}
+
+func TestSqidsMinLength(t *testing.T) {
+	s, _ := NewSqids(SqidsOptions{MinLength: len(DefaultSqidsAlphabet)})
+
+	id, _ := s.Encode(1, 2, 3)
+	if want := "86Rf07xd4zBmiJXQG6otHEbew02c3PWsUOLZxADhCpKj7aVFv9I8RquYrNlSTM"; id != want {
+		t.Errorf("Encode() = %q, want %q", id, want)
+	}
+
+	for _, n := range []uint64{0, 1, 1000, math.MaxUint64} {
+		id, _ := s.Encode(n)
+		if len(id) < len(DefaultSqidsAlphabet) {
+			t.Errorf("Encode(%d) = %q, shorter than the minimum length", n, id)
+		}
+		if got := s.Decode(id); len(got) != 1 || got[0] != n {
+			t.Errorf("Decode(Encode(%d)) = %v", n, got)
+		}
+	}
+}
+
+func TestSqidsRoundTrip(t *testing.T) {
+	s, _ := NewSqids(SqidsOptions{Alphabet: "0123456789abcdef", MinLength: 10})
+	generator, _ := New(1, 1)
+
+	inputs := [][]uint64{
+		{0},
+		{math.MaxUint64},
+		{uint64(generator.Generate())},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		{math.MaxUint64, 0, math.MaxUint64},
+	}
+	for _, numbers := range inputs {
+		id, err := s.Encode(numbers...)
+		if err != nil {
+			t.Fatalf("Encode(%v) error = %v", numbers, err)
+		}
+		if strings.Trim(id, "0123456789abcdef") != "" {
+			t.Errorf("Encode(%v) = %q uses characters outside the alphabet", numbers, id)
+		}
+		if got := s.Decode(id); !reflect.DeepEqual(got, numbers) {
+			t.Errorf("Decode(%q) = %v, want %v", id, got, numbers)
+		}
+	}
+}
+
+func TestSqidsBlocklist(t *testing.T) {
+	plain, _ := NewSqids(SqidsOptions{Blocklist: []string{}})
+	blocked, _ := NewSqids(SqidsOptions{Blocklist: []string{"86Rf07"}})
+
+	// The ID for [1 2 3] is blocked, so a different one is generated
+	id, err := blocked.Encode(1, 2, 3)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if id == "86Rf07" {
+		t.Errorf("Encode() returned the blocked ID %q", id)
+	}
+	if got := blocked.Decode(id); !reflect.DeepEqual(got, []uint64{1, 2, 3}) {
+		t.Errorf("Decode(%q) = %v", id, got)
+	}
+
+	// Matching is case-insensitive and words with characters outside the alphabet are ignored
+	upper, _ := NewSqids(SqidsOptions{Blocklist: []string{"86RF07", "héllo"}})
+	if id, _ := upper.Encode(1, 2, 3); id == "86Rf07" {
+		t.Errorf("Encode() ignored a blocklist word in another case")
+	}
+	if id, _ := plain.Encode(1, 2, 3); id != "86Rf07" {
+		t.Errorf("Encode() with empty blocklist = %q, want 86Rf07", id)
+	}
+
+	// Every candidate blocked
+	tiny, _ := NewSqids(SqidsOptions{Alphabet: "abc", MinLength: 3, Blocklist: []string{"cab", "abc", "bca"}})
+	if _, err := tiny.Encode(0); !errors.Is(err, ErrSqidsBlocked) {
+		t.Errorf("Encode() error = %v, want ErrSqidsBlocked", err)
+	}
+}
+
+func TestSqidsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    SqidsOptions
+		wantErr error
+	}{
+		{"short alphabet", SqidsOptions{Alphabet: "ab"}, ErrInvalidSqidsAlphabet},
+		{"repeated character", SqidsOptions{Alphabet: "abca"}, ErrInvalidSqidsAlphabet},
+		{"multibyte character", SqidsOptions{Alphabet: "abcé"}, ErrInvalidSqidsAlphabet},
+		{"negative min length", SqidsOptions{MinLength: -1}, ErrInvalidSqidsMinLength},
+		{"min length too large", SqidsOptions{MinLength: MaxSqidsMinLength + 1}, ErrInvalidSqidsMinLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSqids(tt.opts); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewSqids() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSqidsDecodeInvalid(t *testing.T) {
+	s, _ := NewSqids(SqidsOptions{})
+	for _, id := range []string{"", "86Rf07!", "*"} {
+		if got := s.Decode(id); len(got) != 0 {
+			t.Errorf("Decode(%q) = %v, want empty", id, got)
+		}
+	}
+}
+
+func TestSnowflakeIDCodec(t *testing.T) {
+	s, _ := NewSqids(SqidsOptions{MinLength: 8})
+	SetSnowflakeIDCodec(s)
+	defer SetSnowflakeIDCodec(nil)
+
+	generator, _ := New(3, 4)
+	id := generator.GenerateID()
+	want, _ := s.Encode(uint64(id))
+
+	text, err := id.MarshalText()
+	if err != nil || string(text) != want {
+		t.Fatalf("MarshalText() = %q, %v, want %q", text, err, want)
+	}
+
+	payload, _ := json.Marshal(struct {
+		ID SnowflakeID `json:"id"`
+	}{id})
+	if string(payload) != `{"id":"`+want+`"}` {
+		t.Errorf("json.Marshal() = %s", payload)
+	}
+
+	var decoded struct {
+		ID SnowflakeID `json:"id"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil || decoded.ID != id {
+		t.Errorf("json.Unmarshal() = %d, %v, want %d", decoded.ID, err, id)
+	}
+
+	// Bare numbers are still decimal
+	if err := json.Unmarshal([]byte(`{"id":`+id.String()+`}`), &decoded); err != nil || decoded.ID != id {
+		t.Errorf("json.Unmarshal(number) = %d, %v, want %d", decoded.ID, err, id)
+	}
+
+	// Non-canonical and multi-number IDs are rejected
+	multi, _ := s.Encode(1, 2)
+	for _, input := range []string{multi, "not-sqids", id.String()} {
+		var v SnowflakeID
+		if err := v.UnmarshalText([]byte(input)); !errors.Is(err, ErrInvalidSnowflakeID) {
+			t.Errorf("UnmarshalText(%q) error = %v, want ErrInvalidSnowflakeID", input, err)
+		}
+	}
+
+	// String is not affected
+	if id.String() != strconv.FormatInt(int64(id), 10) {
+		t.Errorf("String() = %q, want decimal", id.String())
+	}
+
+	SetSnowflakeIDCodec(nil)
+	if text, _ := id.MarshalText(); string(text) != id.String() {
+		t.Errorf("MarshalText() after reset = %q, want decimal", text)
+	}
+}
@@ -0,0 +1,237 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen/encoding"
+)
+
+// ErrTimeOutOfRange is returned when a time cannot be represented by an ID format,
+// e.g. a time before the epoch or past the largest timestamp the format can hold
+var ErrTimeOutOfRange = errors.New("time is outside the range of the ID format")
+
+// Time-range helpers
+//
+// Time-ordered IDs sort by their timestamp first, so all IDs created in [t1, t2]
+// lie between the smallest possible ID for t1 and the largest possible ID for t2.
+// This allows range queries on the primary key without a separate created_at index:
+//
+//	lo, _ := idgen.SnowflakeMinForTime(from, idgen.DefaultLayout, idgen.DefaultEpoch)
+//	hi, _ := idgen.SnowflakeMaxForTime(to, idgen.DefaultLayout, idgen.DefaultEpoch)
+//	rows, err := db.Query("SELECT * FROM orders WHERE id BETWEEN $1 AND $2", lo, hi)
+//
+// Times are truncated to the resolution of the format: milliseconds for Snowflake,
+// UUIDv7 and ULID, seconds for KSUID. The Max functions include every ID of that
+// millisecond (or second).
+
+// maxTimestamp48 is the largest millisecond timestamp of UUIDv7 and ULID
+const maxTimestamp48 = 1<<48 - 1
+
+// SnowflakeMinForTime returns the smallest Snowflake ID that a generator with
+// the given layout and epoch can produce at time t.
+//
+// Parameters:
+//   - t: The time, truncated to the millisecond
+//   - layout: The bit layout of the IDs
+//   - epoch: The generator epoch in milliseconds since Unix epoch
+//
+// Returns:
+//   - int64: The smallest ID for t
+//   - error: ErrInvalidLayout, or ErrTimeOutOfRange if t is before the epoch or past the layout lifetime
+//
+// Example:
+//
+//	since, _ := idgen.SnowflakeMinForTime(time.Now().Add(-24*time.Hour), idgen.DefaultLayout, idgen.DefaultEpoch)
+//	rows, err := db.Query("SELECT * FROM orders WHERE id >= $1", since)
+func SnowflakeMinForTime(t time.Time, layout Layout, epoch int64) (int64, error) {
+	elapsed, err := snowflakeElapsed(t, layout, epoch)
+	if err != nil {
+		return 0, err
+	}
+	return elapsed << layout.timestampShift(), nil
+}
+
+// SnowflakeMaxForTime returns the largest Snowflake ID that a generator with
+// the given layout and epoch can produce at time t.
+//
+// Parameters:
+//   - t: The time, truncated to the millisecond
+//   - layout: The bit layout of the IDs
+//   - epoch: The generator epoch in milliseconds since Unix epoch
+//
+// Returns:
+//   - int64: The largest ID for t
+//   - error: ErrInvalidLayout, or ErrTimeOutOfRange if t is before the epoch or past the layout lifetime
+func SnowflakeMaxForTime(t time.Time, layout Layout, epoch int64) (int64, error) {
+	elapsed, err := snowflakeElapsed(t, layout, epoch)
+	if err != nil {
+		return 0, err
+	}
+	shift := layout.timestampShift()
+	return elapsed<<shift | int64(fieldMask(shift)), nil
+}
+
+// snowflakeElapsed returns the timestamp field for t, checking that it fits the layout
+// and that the resulting IDs are positive
+func snowflakeElapsed(t time.Time, layout Layout, epoch int64) (int64, error) {
+	if err := layout.Validate(); err != nil {
+		return 0, err
+	}
+
+	elapsed := t.UnixMilli() - epoch
+	if elapsed < 0 {
+		return 0, fmt.Errorf("%w: %s is before the epoch", ErrTimeOutOfRange, t.UTC().Format(time.RFC3339Nano))
+	}
+
+	if elapsed > layout.timestampLimit() {
+		return 0, fmt.Errorf("%w: %s is past the layout lifetime", ErrTimeOutOfRange, t.UTC().Format(time.RFC3339Nano))
+	}
+	return elapsed, nil
+}
+
+// UUIDv7MinForTime returns the smallest UUID v7 for time t.
+// Version and variant bits are set, so the result is a valid UUID v7.
+//
+// Parameters:
+//   - t: The time, truncated to the millisecond
+//
+// Returns:
+//   - UUID: The smallest UUID v7 for t
+//   - error: ErrTimeOutOfRange if t is before 1970 or does not fit in 48 bits
+//
+// Example:
+//
+//	lo, _ := idgen.UUIDv7MinForTime(from)
+//	hi, _ := idgen.UUIDv7MaxForTime(to)
+//	rows, err := db.Query("SELECT * FROM events WHERE id BETWEEN $1 AND $2", lo.String(), hi.String())
+func UUIDv7MinForTime(t time.Time) (UUID, error) {
+	var uuid UUID
+	if err := putTimestamp48(uuid[:], t); err != nil {
+		return UUID{}, err
+	}
+	uuid[6] = 0x70
+	uuid[8] = 0x80
+	return uuid, nil
+}
+
+// UUIDv7MaxForTime returns the largest UUID v7 for time t.
+// Version and variant bits are set, so the result is a valid UUID v7.
+//
+// Parameters:
+//   - t: The time, truncated to the millisecond
+//
+// Returns:
+//   - UUID: The largest UUID v7 for t
+//   - error: ErrTimeOutOfRange if t is before 1970 or does not fit in 48 bits
+func UUIDv7MaxForTime(t time.Time) (UUID, error) {
+	var uuid UUID
+	if err := putTimestamp48(uuid[:], t); err != nil {
+		return UUID{}, err
+	}
+	for i := 6; i < len(uuid); i++ {
+		uuid[i] = 0xff
+	}
+	uuid[6] = 0x7f
+	uuid[8] = 0xbf
+	return uuid, nil
+}
+
+// ULIDMinForTime returns the smallest ULID for time t, in its canonical
+// 26-character Crockford base32 form.
+//
+// Parameters:
+//   - t: The time, truncated to the millisecond
+//
+// Returns:
+//   - string: The smallest ULID for t
+//   - error: ErrTimeOutOfRange if t is before 1970 or does not fit in 48 bits
+func ULIDMinForTime(t time.Time) (string, error) {
+	var b [16]byte
+	if err := putTimestamp48(b[:], t); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(encoding.Crockford32, b[:]), nil
+}
+
+// ULIDMaxForTime returns the largest ULID for time t, in its canonical
+// 26-character Crockford base32 form.
+//
+// Parameters:
+//   - t: The time, truncated to the millisecond
+//
+// Returns:
+//   - string: The largest ULID for t
+//   - error: ErrTimeOutOfRange if t is before 1970 or does not fit in 48 bits
+func ULIDMaxForTime(t time.Time) (string, error) {
+	var b [16]byte
+	if err := putTimestamp48(b[:], t); err != nil {
+		return "", err
+	}
+	for i := 6; i < len(b); i++ {
+		b[i] = 0xff
+	}
+	return encoding.EncodeToString(encoding.Crockford32, b[:]), nil
+}
+
+// KSUIDMinForTime returns the smallest KSUID for time t, in its canonical
+// 27-character base62 form.
+//
+// Parameters:
+//   - t: The time, truncated to the second
+//
+// Returns:
+//   - string: The smallest KSUID for t
+//   - error: ErrTimeOutOfRange if t is before KSUIDEpoch or does not fit in 32 bits
+func KSUIDMinForTime(t time.Time) (string, error) {
+	var b [20]byte
+	if err := putKSUIDTimestamp(b[:], t); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(encoding.Base62, b[:]), nil
+}
+
+// KSUIDMaxForTime returns the largest KSUID for time t, in its canonical
+// 27-character base62 form.
+//
+// Parameters:
+//   - t: The time, truncated to the second
+//
+// Returns:
+//   - string: The largest KSUID for t
+//   - error: ErrTimeOutOfRange if t is before KSUIDEpoch or does not fit in 32 bits
+func KSUIDMaxForTime(t time.Time) (string, error) {
+	var b [20]byte
+	if err := putKSUIDTimestamp(b[:], t); err != nil {
+		return "", err
+	}
+	for i := 4; i < len(b); i++ {
+		b[i] = 0xff
+	}
+	return encoding.EncodeToString(encoding.Base62, b[:]), nil
+}
+
+// putTimestamp48 writes the Unix millisecond timestamp of t into the first 6 bytes of dst
+func putTimestamp48(dst []byte, t time.Time) error {
+	ms := t.UnixMilli()
+	if ms < 0 || ms > maxTimestamp48 {
+		return fmt.Errorf("%w: %s does not fit in 48 bits", ErrTimeOutOfRange, t.UTC().Format(time.RFC3339Nano))
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(ms))
+	copy(dst[:6], buf[2:])
+	return nil
+}
+
+// putKSUIDTimestamp writes the KSUID timestamp of t into the first 4 bytes of dst
+func putKSUIDTimestamp(dst []byte, t time.Time) error {
+	seconds := t.Unix() - KSUIDEpoch
+	if seconds < 0 || seconds > math.MaxUint32 {
+		return fmt.Errorf("%w: %s is outside the KSUID lifetime", ErrTimeOutOfRange, t.UTC().Format(time.RFC3339Nano))
+	}
+	binary.BigEndian.PutUint32(dst[:4], uint32(seconds))
+	return nil
+}
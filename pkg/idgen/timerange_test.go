@@ -0,0 +1,185 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSnowflakeTimeRange(t *testing.T) {
+	layouts := []struct {
+		name   string
+		layout Layout
+		epoch  int64
+	}{
+		{"default", DefaultLayout, DefaultEpoch},
+		{"discord", DiscordLayout, DiscordEpoch},
+		{"instagram", InstagramLayout, InstagramEpoch},
+		{"mastodon", MastodonLayout, MastodonEpoch},
+	}
+
+	for _, tt := range layouts {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, _ := NewWithLayout(0, 0, tt.layout, tt.epoch)
+			id := generator.Generate()
+			created := time.UnixMilli(generator.ExtractTimestamp(id))
+
+			lo, err := SnowflakeMinForTime(created, tt.layout, tt.epoch)
+			if err != nil {
+				t.Fatalf("SnowflakeMinForTime() error = %v", err)
+			}
+			hi, err := SnowflakeMaxForTime(created, tt.layout, tt.epoch)
+			if err != nil {
+				t.Fatalf("SnowflakeMaxForTime() error = %v", err)
+			}
+			if id < lo || id > hi {
+				t.Errorf("ID %d outside [%d, %d]", id, lo, hi)
+			}
+
+			// Neighbouring milliseconds do not overlap
+			next, _ := SnowflakeMinForTime(created.Add(time.Millisecond), tt.layout, tt.epoch)
+			if next != hi+1 {
+				t.Errorf("Min of next millisecond = %d, want %d", next, hi+1)
+			}
+			if generator.ExtractTimestamp(lo) != created.UnixMilli() || generator.ExtractTimestamp(hi) != created.UnixMilli() {
+				t.Errorf("Bounds decode to %d and %d, want %d",
+					generator.ExtractTimestamp(lo), generator.ExtractTimestamp(hi), created.UnixMilli())
+			}
+		})
+	}
+}
+
+func TestSnowflakeTimeRangeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       time.Time
+		layout  Layout
+		wantErr error
+	}{
+		{"before epoch", time.UnixMilli(DefaultEpoch - 1), DefaultLayout, ErrTimeOutOfRange},
+		{"past lifetime", time.UnixMilli(DefaultEpoch + 1<<41), DefaultLayout, ErrTimeOutOfRange},
+		{"invalid layout", time.Now(), Layout{}, ErrInvalidLayout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SnowflakeMinForTime(tt.t, tt.layout, DefaultEpoch); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SnowflakeMinForTime() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := SnowflakeMaxForTime(tt.t, tt.layout, DefaultEpoch); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SnowflakeMaxForTime() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The last millisecond of the layout is still valid
+	if _, err := SnowflakeMaxForTime(time.UnixMilli(DefaultEpoch+1<<41-1), DefaultLayout, DefaultEpoch); err != nil {
+		t.Errorf("SnowflakeMaxForTime() at end of lifetime error = %v", err)
+	}
+}
+
+func TestUUIDv7TimeRange(t *testing.T) {
+	uuid, err := NewUUIDv7()
+	if err != nil {
+		t.Fatalf("NewUUIDv7() error = %v", err)
+	}
+	created := ExtractTimeFromUUIDv7(uuid)
+
+	lo, err := UUIDv7MinForTime(created)
+	if err != nil {
+		t.Fatalf("UUIDv7MinForTime() error = %v", err)
+	}
+	hi, err := UUIDv7MaxForTime(created)
+	if err != nil {
+		t.Fatalf("UUIDv7MaxForTime() error = %v", err)
+	}
+
+	if lo.String() > uuid.String() || uuid.String() > hi.String() {
+		t.Errorf("UUID %s outside [%s, %s]", uuid, lo, hi)
+	}
+	for _, bound := range []UUID{lo, hi} {
+		if bound[6]>>4 != 7 {
+			t.Errorf("Bound %s has version %d, want 7", bound, bound[6]>>4)
+		}
+		if bound[8]&0xc0 != 0x80 {
+			t.Errorf("Bound %s has invalid variant", bound)
+		}
+	}
+
+	if got := lo.String(); got[14] != '7' || got[19] != '8' {
+		t.Errorf("Min UUID %s, want version 7 and variant 8", got)
+	}
+	if got := hi.String(); got[24:] != "ffffffffffff" || got[19] != 'b' {
+		t.Errorf("Max UUID %s, want variant b and all random bits set", got)
+	}
+
+	if _, err := UUIDv7MinForTime(time.UnixMilli(-1)); !errors.Is(err, ErrTimeOutOfRange) {
+		t.Errorf("Expected ErrTimeOutOfRange before 1970, got %v", err)
+	}
+}
+
+func TestULIDTimeRange(t *testing.T) {
+	// Sample ULID from the specification, created at 1465824320894 ms
+	const sample = "01AN4Z07BY79KA1307SR9X4MV3"
+	created := time.UnixMilli(1465824320894)
+
+	lo, err := ULIDMinForTime(created)
+	if err != nil {
+		t.Fatalf("ULIDMinForTime() error = %v", err)
+	}
+	hi, err := ULIDMaxForTime(created)
+	if err != nil {
+		t.Fatalf("ULIDMaxForTime() error = %v", err)
+	}
+
+	if lo != "01AN4Z07BY0000000000000000" || hi != "01AN4Z07BYZZZZZZZZZZZZZZZZ" {
+		t.Errorf("Bounds = [%s, %s]", lo, hi)
+	}
+	if lo > sample || sample > hi {
+		t.Errorf("ULID %s outside [%s, %s]", sample, lo, hi)
+	}
+
+	if got, _ := ULIDMaxForTime(time.UnixMilli(maxTimestamp48)); got != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("Largest ULID = %s, want 7ZZZZZZZZZZZZZZZZZZZZZZZZZ", got)
+	}
+	if _, err := ULIDMinForTime(time.UnixMilli(maxTimestamp48 + 1)); !errors.Is(err, ErrTimeOutOfRange) {
+		t.Errorf("Expected ErrTimeOutOfRange past 48 bits, got %v", err)
+	}
+}
+
+func TestKSUIDTimeRange(t *testing.T) {
+	// Sample KSUID from the specification, created 107608047 seconds after KSUIDEpoch
+	const sample = "0ujtsYcgvSTl8PAuAdqWYSMnLOv"
+	created := time.Unix(KSUIDEpoch+107608047, 0)
+
+	lo, err := KSUIDMinForTime(created)
+	if err != nil {
+		t.Fatalf("KSUIDMinForTime() error = %v", err)
+	}
+	hi, err := KSUIDMaxForTime(created)
+	if err != nil {
+		t.Fatalf("KSUIDMaxForTime() error = %v", err)
+	}
+	if len(lo) != 27 || len(hi) != 27 {
+		t.Fatalf("Bounds have lengths %d and %d, want 27", len(lo), len(hi))
+	}
+	if lo > sample || sample > hi {
+		t.Errorf("KSUID %s outside [%s, %s]", sample, lo, hi)
+	}
+
+	// The next second starts right after the previous maximum
+	next, _ := KSUIDMinForTime(created.Add(time.Second))
+	if next <= hi {
+		t.Errorf("Min of next second %s not after %s", next, hi)
+	}
+
+	if got, _ := KSUIDMinForTime(time.Unix(KSUIDEpoch, 0)); got != "000000000000000000000000000" {
+		t.Errorf("Smallest KSUID = %s", got)
+	}
+	if got, _ := KSUIDMaxForTime(time.Unix(KSUIDEpoch+1<<32-1, 0)); got != "aWgEPTl1tmebfsQzFP4bxwgy80V" {
+		t.Errorf("Largest KSUID = %s, want aWgEPTl1tmebfsQzFP4bxwgy80V", got)
+	}
+	if _, err := KSUIDMinForTime(time.Unix(KSUIDEpoch-1, 0)); !errors.Is(err, ErrTimeOutOfRange) {
+		t.Errorf("Expected ErrTimeOutOfRange before KSUIDEpoch, got %v", err)
+	}
+}
@@ -0,0 +1,373 @@
+package idgen
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen/encoding"
+)
+
+// TypeID errors
+var (
+	// ErrInvalidTypeID is returned when a TypeID cannot be parsed
+	ErrInvalidTypeID = errors.New("invalid TypeID")
+
+	// ErrInvalidTypeIDPrefix is returned when a TypeID prefix does not follow the specification
+	ErrInvalidTypeIDPrefix = errors.New("invalid TypeID prefix")
+
+	// ErrTypeIDPrefixMismatch is returned when a TypeID has a different prefix than expected
+	ErrTypeIDPrefixMismatch = errors.New("unexpected TypeID prefix")
+)
+
+const (
+	// MaxTypeIDPrefixLen is the maximum length of a TypeID prefix
+	MaxTypeIDPrefixLen = 63
+
+	// typeIDSuffixLen is the length of the Base32 suffix
+	typeIDSuffixLen = 26
+)
+
+// typeIDEncoding is the strict lowercase Crockford Base32 alphabet required by the TypeID specification
+var typeIDEncoding = encoding.NewRadix(strings.ToLower(encoding.Crockford32Alphabet))
+
+// Prefix names the type of a TypeID. Implement it on an empty struct to declare
+// a distinct ID type per entity:
+//
+//	type UserPrefix struct{}
+//
+//	func (UserPrefix) Prefix() string { return "user" }
+//
+//	type UserID = idgen.TypeID[UserPrefix]
+type Prefix interface {
+	// Prefix returns the TypeID prefix: up to 63 lowercase ASCII letters and underscores,
+	// starting and ending with a letter. An empty prefix is allowed.
+	Prefix() string
+}
+
+// TypeID is a type-safe, prefixed identifier following the TypeID specification
+// (https://github.com/jetify-com/typeid): a lowercase type prefix, an underscore,
+// and a UUID (v7 when generated) in 26 characters of lowercase Crockford Base32.
+//
+//	user_01h455vb4pex5vsknk084sn02q
+//
+// TypeIDs with different P are distinct Go types, so a UserID cannot be passed
+// where an OrderID is expected. TypeIDs sort by creation time, like UUIDv7.
+//
+// The zero value is the TypeID of the nil UUID. TypeID implements encoding.TextMarshaler,
+// so it is encoded as a JSON string, and sql.Scanner and driver.Valuer, storing the
+// string form.
+type TypeID[P Prefix] struct {
+	uuid UUID
+}
+
+// NewTypeID generates a TypeID with a new UUID v7 suffix.
+//
+// Returns:
+//   - TypeID[P]: A new TypeID
+//   - error: ErrInvalidTypeIDPrefix if P has an invalid prefix, or a UUID generation error
+//
+// Example:
+//
+//	id, err := idgen.NewTypeID[UserPrefix]()
+//	fmt.Println(id) // user_01h455vb4pex5vsknk084sn02q
+func NewTypeID[P Prefix]() (TypeID[P], error) {
+	var p P
+	if err := validateTypeIDPrefix(p.Prefix()); err != nil {
+		return TypeID[P]{}, err
+	}
+
+	uuid, err := NewUUIDv7()
+	if err != nil {
+		return TypeID[P]{}, err
+	}
+	return TypeID[P]{uuid: uuid}, nil
+}
+
+// TypeIDFromUUID wraps an existing UUID in a TypeID.
+func TypeIDFromUUID[P Prefix](uuid UUID) TypeID[P] {
+	return TypeID[P]{uuid: uuid}
+}
+
+// ParseTypeID parses a TypeID and checks that its prefix is the one of P.
+//
+// Returns:
+//   - TypeID[P]: The parsed TypeID
+//   - error: ErrInvalidTypeID if s is malformed, ErrTypeIDPrefixMismatch if the prefix differs
+//
+// Example:
+//
+//	id, err := idgen.ParseTypeID[UserPrefix](r.PathValue("id"))
+//	if errors.Is(err, idgen.ErrTypeIDPrefixMismatch) {
+//	    // e.g. an order ID was passed to the users endpoint
+//	}
+func ParseTypeID[P Prefix](s string) (TypeID[P], error) {
+	prefix, uuid, err := parseTypeID(s)
+	if err != nil {
+		return TypeID[P]{}, err
+	}
+
+	var p P
+	if want := p.Prefix(); prefix != want {
+		return TypeID[P]{}, fmt.Errorf("%w: %q, want %q", ErrTypeIDPrefixMismatch, prefix, want)
+	}
+	return TypeID[P]{uuid: uuid}, nil
+}
+
+// Prefix returns the type prefix.
+func (id TypeID[P]) Prefix() string {
+	var p P
+	return p.Prefix()
+}
+
+// UUID returns the UUID suffix.
+func (id TypeID[P]) UUID() UUID {
+	return id.uuid
+}
+
+// IsZero reports whether the TypeID holds the nil UUID.
+func (id TypeID[P]) IsZero() bool {
+	return id.uuid == UUID{}
+}
+
+// Any returns the TypeID with its prefix stored at runtime.
+func (id TypeID[P]) Any() AnyTypeID {
+	return AnyTypeID{prefix: id.Prefix(), uuid: id.uuid}
+}
+
+// String returns the TypeID in its canonical form.
+func (id TypeID[P]) String() string {
+	return formatTypeID(id.Prefix(), id.uuid)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id TypeID[P]) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The prefix must be the one of P.
+func (id *TypeID[P]) UnmarshalText(text []byte) error {
+	parsed, err := ParseTypeID[P](string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing the TypeID as a string.
+func (id TypeID[P]) Value() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// Scan implements sql.Scanner.
+// It accepts the TypeID string form, or a bare UUID read from a UUID column
+// as a canonical string or 16 bytes. NULL scans as the zero TypeID.
+func (id *TypeID[P]) Scan(src any) error {
+	if src == nil {
+		*id = TypeID[P]{}
+		return nil
+	}
+	text, uuid, err := scanTypeID(src)
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		*id = TypeID[P]{uuid: uuid}
+		return nil
+	}
+	return id.UnmarshalText([]byte(text))
+}
+
+// AnyTypeID is a TypeID whose prefix is only known at runtime,
+// e.g. to accept IDs of several types in one endpoint.
+type AnyTypeID struct {
+	prefix string
+	uuid   UUID
+}
+
+// NewAnyTypeID generates a TypeID with the given prefix and a new UUID v7 suffix.
+//
+// Returns:
+//   - AnyTypeID: A new TypeID
+//   - error: ErrInvalidTypeIDPrefix if prefix is invalid, or a UUID generation error
+func NewAnyTypeID(prefix string) (AnyTypeID, error) {
+	if err := validateTypeIDPrefix(prefix); err != nil {
+		return AnyTypeID{}, err
+	}
+
+	uuid, err := NewUUIDv7()
+	if err != nil {
+		return AnyTypeID{}, err
+	}
+	return AnyTypeID{prefix: prefix, uuid: uuid}, nil
+}
+
+// AnyTypeIDFromUUID wraps an existing UUID in a TypeID with the given prefix.
+//
+// Returns:
+//   - AnyTypeID: The TypeID
+//   - error: ErrInvalidTypeIDPrefix if prefix is invalid
+func AnyTypeIDFromUUID(prefix string, uuid UUID) (AnyTypeID, error) {
+	if err := validateTypeIDPrefix(prefix); err != nil {
+		return AnyTypeID{}, err
+	}
+	return AnyTypeID{prefix: prefix, uuid: uuid}, nil
+}
+
+// ParseAnyTypeID parses a TypeID with any valid prefix.
+//
+// Returns:
+//   - AnyTypeID: The parsed TypeID
+//   - error: ErrInvalidTypeID if s is malformed
+func ParseAnyTypeID(s string) (AnyTypeID, error) {
+	prefix, uuid, err := parseTypeID(s)
+	if err != nil {
+		return AnyTypeID{}, err
+	}
+	return AnyTypeID{prefix: prefix, uuid: uuid}, nil
+}
+
+// Prefix returns the type prefix.
+func (id AnyTypeID) Prefix() string {
+	return id.prefix
+}
+
+// UUID returns the UUID suffix.
+func (id AnyTypeID) UUID() UUID {
+	return id.uuid
+}
+
+// IsZero reports whether the TypeID has no prefix and holds the nil UUID.
+func (id AnyTypeID) IsZero() bool {
+	return id.prefix == "" && id.uuid == UUID{}
+}
+
+// String returns the TypeID in its canonical form.
+func (id AnyTypeID) String() string {
+	return formatTypeID(id.prefix, id.uuid)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id AnyTypeID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *AnyTypeID) UnmarshalText(text []byte) error {
+	parsed, err := ParseAnyTypeID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing the TypeID as a string.
+func (id AnyTypeID) Value() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts the TypeID string form only,
+// because a bare UUID does not carry the prefix. NULL scans as the zero AnyTypeID.
+func (id *AnyTypeID) Scan(src any) error {
+	if src == nil {
+		*id = AnyTypeID{}
+		return nil
+	}
+	text, _, err := scanTypeID(src)
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		return fmt.Errorf("%w: a bare UUID has no prefix", ErrInvalidTypeID)
+	}
+	return id.UnmarshalText([]byte(text))
+}
+
+// formatTypeID joins a prefix and the encoded UUID
+func formatTypeID(prefix string, uuid UUID) string {
+	n := len(prefix)
+	if n > 0 {
+		n++
+	}
+
+	buf := make([]byte, n+typeIDSuffixLen)
+	if n > 0 {
+		copy(buf, prefix)
+		buf[n-1] = '_'
+	}
+	typeIDEncoding.Encode(buf[n:], uuid[:])
+	return string(buf)
+}
+
+// parseTypeID splits s into its prefix and UUID
+func parseTypeID(s string) (string, UUID, error) {
+	prefix, suffix := "", s
+	if i := strings.LastIndexByte(s, '_'); i >= 0 {
+		prefix, suffix = s[:i], s[i+1:]
+		if prefix == "" {
+			return "", UUID{}, fmt.Errorf("%w: %q has a separator without a prefix", ErrInvalidTypeID, s)
+		}
+	}
+
+	if err := validateTypeIDPrefix(prefix); err != nil {
+		return "", UUID{}, fmt.Errorf("%w: %w", ErrInvalidTypeID, err)
+	}
+
+	var uuid UUID
+	if err := typeIDEncoding.Decode(uuid[:], []byte(suffix)); err != nil {
+		return "", UUID{}, fmt.Errorf("%w: suffix: %w", ErrInvalidTypeID, err)
+	}
+	return prefix, uuid, nil
+}
+
+// validateTypeIDPrefix checks a prefix against the TypeID specification
+func validateTypeIDPrefix(prefix string) error {
+	if len(prefix) > MaxTypeIDPrefixLen {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidTypeIDPrefix, MaxTypeIDPrefixLen)
+	}
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if (c < 'a' || c > 'z') && c != '_' {
+			return fmt.Errorf("%w: %q contains %q", ErrInvalidTypeIDPrefix, prefix, c)
+		}
+	}
+	if prefix != "" && (prefix[0] == '_' || prefix[len(prefix)-1] == '_') {
+		return fmt.Errorf("%w: %q starts or ends with an underscore", ErrInvalidTypeIDPrefix, prefix)
+	}
+	return nil
+}
+
+// scanTypeID extracts the TypeID text from a database value,
+// or the UUID when the value is a bare UUID
+func scanTypeID(src any) (string, UUID, error) {
+	switch v := src.(type) {
+	case string:
+		return scanTypeIDString(v)
+	case []byte:
+		if len(v) == 16 {
+			return "", UUID(v), nil
+		}
+		return scanTypeIDString(string(v))
+	default:
+		return "", UUID{}, fmt.Errorf("%w: cannot scan %T", ErrInvalidTypeID, src)
+	}
+}
+
+// scanTypeIDString recognizes canonical UUID strings, returning other strings unchanged
+func scanTypeIDString(s string) (string, UUID, error) {
+	if len(s) == 36 && strings.Count(s, "-") == 4 {
+		uuid, err := ParseUUID(s)
+		if err != nil {
+			return "", UUID{}, fmt.Errorf("%w: %w", ErrInvalidTypeID, err)
+		}
+		return "", uuid, nil
+	}
+	if s == "" {
+		return "", UUID{}, fmt.Errorf("%w: empty value", ErrInvalidTypeID)
+	}
+	return s, UUID{}, nil
+}
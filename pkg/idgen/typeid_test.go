@@ -0,0 +1,245 @@
+package idgen
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testUserPrefix struct{}
+
+func (testUserPrefix) Prefix() string { return "user" }
+
+type testOrderPrefix struct{}
+
+func (testOrderPrefix) Prefix() string { return "order" }
+
+type testBadPrefix struct{}
+
+func (testBadPrefix) Prefix() string { return "User" }
+
+type (
+	testUserID  = TypeID[testUserPrefix]
+	testOrderID = TypeID[testOrderPrefix]
+)
+
+func mustParseUUID(t *testing.T, s string) UUID {
+	t.Helper()
+	uuid, err := ParseUUID(s)
+	if err != nil {
+		t.Fatalf("ParseUUID(%q) error = %v", s, err)
+	}
+	return uuid
+}
+
+func TestTypeIDSpecValid(t *testing.T) {
+	// Cases from the TypeID specification test suite
+	tests := []struct {
+		name   string
+		typeid string
+		prefix string
+		uuid   string
+	}{
+		{"nil", "00000000000000000000000000", "", "00000000-0000-0000-0000-000000000000"},
+		{"one", "00000000000000000000000001", "", "00000000-0000-0000-0000-000000000001"},
+		{"ten", "0000000000000000000000000a", "", "00000000-0000-0000-0000-00000000000a"},
+		{"sixteen", "0000000000000000000000000g", "", "00000000-0000-0000-0000-000000000010"},
+		{"thirty-two", "00000000000000000000000010", "", "00000000-0000-0000-0000-000000000020"},
+		{"max-valid", "7zzzzzzzzzzzzzzzzzzzzzzzzz", "", "ffffffff-ffff-ffff-ffff-ffffffffffff"},
+		{"valid-alphabet", "prefix_0123456789abcdefghjkmnpqrs", "prefix", "0110c853-1d09-52d8-d73e-1194e95b5f19"},
+		{"valid-uuidv7", "prefix_01h455vb4pex5vsknk084sn02q", "prefix", "01890a5d-ac96-774b-bcce-b302099a8057"},
+		{"prefix-underscore", "pre_fix_00000000000000000000000000", "pre_fix", "00000000-0000-0000-0000-000000000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uuid := mustParseUUID(t, tt.uuid)
+
+			id, err := ParseAnyTypeID(tt.typeid)
+			if err != nil {
+				t.Fatalf("ParseAnyTypeID() error = %v", err)
+			}
+			if id.Prefix() != tt.prefix || id.UUID() != uuid {
+				t.Errorf("ParseAnyTypeID() = %q/%s, want %q/%s", id.Prefix(), id.UUID(), tt.prefix, uuid)
+			}
+
+			encoded, err := AnyTypeIDFromUUID(tt.prefix, uuid)
+			if err != nil {
+				t.Fatalf("AnyTypeIDFromUUID() error = %v", err)
+			}
+			if encoded.String() != tt.typeid {
+				t.Errorf("String() = %q, want %q", encoded.String(), tt.typeid)
+			}
+		})
+	}
+}
+
+func TestTypeIDSpecInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		typeid string
+	}{
+		{"prefix-uppercase", "PREFIX_00000000000000000000000000"},
+		{"prefix-numeric", "12345_00000000000000000000000000"},
+		{"prefix-period", "pre.fix_00000000000000000000000000"},
+		{"prefix-non-ascii", "préfix_00000000000000000000000000"},
+		{"prefix-spaces", "  prefix_00000000000000000000000000"},
+		{"prefix-64-chars", strings.Repeat("a", 64) + "_00000000000000000000000000"},
+		{"separator-empty-prefix", "_00000000000000000000000000"},
+		{"separator-empty", "_"},
+		{"suffix-short", "prefix_1234567890123456789012345"},
+		{"suffix-long", "prefix_123456789012345678901234567"},
+		{"suffix-spaces", "prefix_1234567890123456789012345 "},
+		{"suffix-uppercase", "prefix_0123456789ABCDEFGHJKMNPQRS"},
+		{"suffix-hyphens", "prefix_123456789-123456789-123456"},
+		{"suffix-wrong-alphabet", "prefix_ooooooiiiiiiuuuuuuulllllll"},
+		{"suffix-ambiguous-crockford", "prefix_i23456789ol23456789oi23456"},
+		{"suffix-overflow", "prefix_8zzzzzzzzzzzzzzzzzzzzzzzzz"},
+		{"prefix-underscore-start", "_prefix_00000000000000000000000000"},
+		{"prefix-underscore-end", "prefix__00000000000000000000000000"},
+		{"empty", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAnyTypeID(tt.typeid); !errors.Is(err, ErrInvalidTypeID) {
+				t.Errorf("ParseAnyTypeID(%q) error = %v, want ErrInvalidTypeID", tt.typeid, err)
+			}
+		})
+	}
+}
+
+func TestTypeIDGeneric(t *testing.T) {
+	id, err := NewTypeID[testUserPrefix]()
+	if err != nil {
+		t.Fatalf("NewTypeID() error = %v", err)
+	}
+
+	s := id.String()
+	if !strings.HasPrefix(s, "user_") || len(s) != len("user_")+26 {
+		t.Errorf("String() = %q", s)
+	}
+	if id.UUID()[6]>>4 != 7 {
+		t.Errorf("Suffix is not a UUID v7: %s", id.UUID())
+	}
+
+	parsed, err := ParseTypeID[testUserPrefix](s)
+	if err != nil || parsed != id {
+		t.Errorf("ParseTypeID() = %v, %v, want %v", parsed, err, id)
+	}
+
+	if _, err := ParseTypeID[testOrderPrefix](s); !errors.Is(err, ErrTypeIDPrefixMismatch) {
+		t.Errorf("ParseTypeID() with another prefix error = %v, want ErrTypeIDPrefixMismatch", err)
+	}
+	if _, err := NewTypeID[testBadPrefix](); !errors.Is(err, ErrInvalidTypeIDPrefix) {
+		t.Errorf("NewTypeID() with invalid prefix error = %v, want ErrInvalidTypeIDPrefix", err)
+	}
+
+	if any := id.Any(); any.String() != s || any.Prefix() != "user" {
+		t.Errorf("Any() = %v", any)
+	}
+	if (testUserID{}).IsZero() != true || id.IsZero() {
+		t.Errorf("IsZero() mismatch")
+	}
+
+	// Generated IDs sort by creation time
+	next, _ := NewTypeID[testUserPrefix]()
+	if next.String() <= s {
+		t.Errorf("TypeIDs not increasing: %q then %q", s, next)
+	}
+}
+
+func TestTypeIDJSON(t *testing.T) {
+	type order struct {
+		ID     testOrderID `json:"id"`
+		UserID testUserID  `json:"user_id"`
+	}
+
+	o := order{
+		ID:     TypeIDFromUUID[testOrderPrefix](mustParseUUID(t, "01890a5d-ac96-774b-bcce-b302099a8057")),
+		UserID: TypeIDFromUUID[testUserPrefix](mustParseUUID(t, "00000000-0000-0000-0000-000000000001")),
+	}
+
+	payload, err := json.Marshal(o)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"id":"order_01h455vb4pex5vsknk084sn02q","user_id":"user_00000000000000000000000001"}`
+	if string(payload) != want {
+		t.Errorf("json.Marshal() = %s, want %s", payload, want)
+	}
+
+	var decoded order
+	if err := json.Unmarshal(payload, &decoded); err != nil || decoded != o {
+		t.Errorf("json.Unmarshal() = %+v, %v", decoded, err)
+	}
+
+	// Swapped IDs are rejected
+	swapped := `{"id":"user_00000000000000000000000001"}`
+	if err := json.Unmarshal([]byte(swapped), &decoded); !errors.Is(err, ErrTypeIDPrefixMismatch) {
+		t.Errorf("json.Unmarshal() with wrong prefix error = %v, want ErrTypeIDPrefixMismatch", err)
+	}
+}
+
+func TestTypeIDSQL(t *testing.T) {
+	uuid := mustParseUUID(t, "01890a5d-ac96-774b-bcce-b302099a8057")
+	id := TypeIDFromUUID[testUserPrefix](uuid)
+
+	value, err := id.Value()
+	if err != nil || value != "user_01h455vb4pex5vsknk084sn02q" {
+		t.Errorf("Value() = %v, %v", value, err)
+	}
+
+	sources := []any{
+		"user_01h455vb4pex5vsknk084sn02q",
+		[]byte("user_01h455vb4pex5vsknk084sn02q"),
+		"01890a5d-ac96-774b-bcce-b302099a8057",
+		uuid[:],
+	}
+	for _, src := range sources {
+		var scanned testUserID
+		if err := scanned.Scan(src); err != nil || scanned != id {
+			t.Errorf("Scan(%v) = %v, %v, want %v", src, scanned, err, id)
+		}
+	}
+
+	var scanned testUserID
+	for _, src := range []any{42, "", "order_01h455vb4pex5vsknk084sn02q"} {
+		if err := scanned.Scan(src); err == nil {
+			t.Errorf("Scan(%v) expected error", src)
+		}
+	}
+
+	var anyID AnyTypeID
+	if err := anyID.Scan("user_01h455vb4pex5vsknk084sn02q"); err != nil || anyID.String() != id.String() {
+		t.Errorf("AnyTypeID.Scan() = %v, %v", anyID, err)
+	}
+	if err := anyID.Scan(uuid[:]); !errors.Is(err, ErrInvalidTypeID) {
+		t.Errorf("AnyTypeID.Scan(bare UUID) error = %v, want ErrInvalidTypeID", err)
+	}
+
+	// NULL columns scan as the zero value
+	scanned = id
+	if err := scanned.Scan(nil); err != nil || scanned != (testUserID{}) {
+		t.Errorf("Scan(nil) = %v, %v, want the zero TypeID", scanned, err)
+	}
+	anyID, _ = ParseAnyTypeID("user_01h455vb4pex5vsknk084sn02q")
+	if err := anyID.Scan(nil); err != nil || anyID != (AnyTypeID{}) {
+		t.Errorf("AnyTypeID.Scan(nil) = %v, %v, want the zero AnyTypeID", anyID, err)
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	uuid, _ := NewUUIDv4()
+	for _, s := range []string{uuid.String(), strings.ToUpper(uuid.String())} {
+		if parsed, err := ParseUUID(s); err != nil || parsed != uuid {
+			t.Errorf("ParseUUID(%q) = %s, %v", s, parsed, err)
+		}
+	}
+	for _, s := range []string{"", "01890a5dac96774bbcceb302099a8057", "01890a5d-ac96-774b-bcce-b302099a805g", "01890a5d+ac96-774b-bcce-b302099a8057"} {
+		if _, err := ParseUUID(s); !errors.Is(err, ErrInvalidUUID) {
+			t.Errorf("ParseUUID(%q) error = %v, want ErrInvalidUUID", s, err)
+		}
+	}
+}
@@ -1,5 +1,14 @@
 package idgen
 
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-utilities-packages/go-lib-id/pkg/idgen/encoding"
+)
+
 // ULID (Universally Unique Lexicographically Sortable Identifier)
 //
 // ULID is designed to be:
@@ -22,6 +31,68 @@ package idgen
 // - Better database index performance (time-ordered)
 // - URL-safe by default
 
+// ErrInvalidULID is returned when a ULID cannot be parsed
+var ErrInvalidULID = errors.New("invalid ULID")
+
+// ULID is a 128-bit ULID. It has the same size as UUID, and the conversion
+// between both is lossless: ULIDFromUUID(u).UUID() == u.
+type ULID [16]byte
+
+// ParseULID parses a ULID in its canonical 26-character Crockford base32 form.
+// Lowercase letters and the aliases I, L (for 1) and O (for 0) are accepted.
+//
+// Returns:
+//   - ULID: The parsed ULID
+//   - error: ErrInvalidULID if s is not a valid ULID
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if err := encoding.Crockford32.Decode(u[:], []byte(s)); err != nil {
+		return ULID{}, fmt.Errorf("%w: %w", ErrInvalidULID, err)
+	}
+	return u, nil
+}
+
+// ULIDFromUUID returns the ULID with the same 128 bits as u.
+// A UUIDv7 becomes a ULID with the same millisecond timestamp and the same sort order.
+func ULIDFromUUID(u UUID) ULID {
+	return ULID(u)
+}
+
+// UUID returns the UUID with the same 128 bits as the ULID
+func (u ULID) UUID() UUID {
+	return UUID(u)
+}
+
+// String returns the canonical 26-character Crockford base32 form of the ULID
+func (u ULID) String() string {
+	return encoding.EncodeToString(encoding.Crockford32, u[:])
+}
+
+// Timestamp returns the timestamp of the ULID in milliseconds since Unix epoch
+func (u ULID) Timestamp() int64 {
+	return int64(binary.BigEndian.Uint64(u[0:8]) >> 16)
+}
+
+// Time returns the timestamp of the ULID as a time.Time in UTC
+func (u ULID) Time() time.Time {
+	return time.UnixMilli(u.Timestamp()).UTC()
+}
+
+// MarshalText implements encoding.TextMarshaler using the canonical form
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (u *ULID) UnmarshalText(text []byte) error {
+	v, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
 // GenerateULID generates a new ULID
 // TODO: Implement ULID generation algorithm
 func GenerateULID() (string, error) {
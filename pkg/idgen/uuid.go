@@ -1,10 +1,12 @@
 package idgen
 
 import (
-	"crypto/rand"
+	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"io"
+	"log/slog"
 	"sync"
 	"time"
 )
@@ -30,17 +32,111 @@ func (u UUID) String() string {
 	return string(buf)
 }
 
+// ParseUUID parses a UUID in the canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+// Hexadecimal digits may be upper or lower case.
+//
+// Returns:
+//   - UUID: The parsed UUID
+//   - error: ErrInvalidUUID if s is not in the canonical form
+func ParseUUID(s string) (UUID, error) {
+	var uuid UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return UUID{}, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+	}
+
+	j := 0
+	for i := 0; i < 36; i += 2 {
+		if i == 8 || i == 13 || i == 18 || i == 23 {
+			i++
+		}
+		hi, ok1 := fromHexChar(s[i])
+		lo, ok2 := fromHexChar(s[i+1])
+		if !ok1 || !ok2 {
+			return UUID{}, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+		}
+		uuid[j] = hi<<4 | lo
+		j++
+	}
+	return uuid, nil
+}
+
+// fromHexChar converts a hexadecimal digit to its value
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// LogValue implements slog.LogValuer, so UUIDs are logged in their canonical string form
+func (u UUID) LogValue() slog.Value {
+	return slog.StringValue(u.String())
+}
+
+// UUIDv4Generator generates random UUID v4 from a configurable entropy source
+type UUIDv4Generator struct {
+	randomSource
+}
+
+var globalUUIDv4Generator = &UUIDv4Generator{}
+
+// NewUUIDv4Generator creates a UUID v4 generator that reads from the shared entropy buffers.
+// Use SetEntropy to read from another source.
+func NewUUIDv4Generator() *UUIDv4Generator {
+	return &UUIDv4Generator{}
+}
+
 // NewUUIDv4 generates a new UUID v4 (random)
 // UUID v4 is a randomly generated UUID with 122 bits of randomness
 // Format: xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx
 // where x is any hexadecimal digit and y is one of 8, 9, a, or b
 func NewUUIDv4() (UUID, error) {
+	if o := uuidv4OverrideFn.Load(); o != nil {
+		return o.fn()
+	}
+	return globalUUIDv4Generator.Generate()
+}
+
+// GenerateString creates a new UUID v4 in canonical form, implementing Generator.
+// It fails with ctx.Err() if ctx is already done.
+func (g *UUIDv4Generator) GenerateString(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	uuid, err := g.Generate()
+	if err != nil {
+		return "", err
+	}
+	return uuid.String(), nil
+}
+
+// SetEntropy sets the source of random bytes. Passing nil restores the shared
+// entropy buffers, which is the default. It is safe to call while the generator is in use.
+//
+// Example:
+//
+//	generator := idgen.NewUUIDv4Generator()
+//	generator.SetEntropy(rand.Reader) // read crypto/rand directly, without buffering
+func (g *UUIDv4Generator) SetEntropy(r io.Reader) {
+	g.setEntropy(r)
+}
+
+// Generate creates a new UUID v4.
+//
+// Returns:
+//   - UUID: The new UUID, or the zero UUID on error
+//   - error: ErrRandomSource wrapping the error of the entropy source
+func (g *UUIDv4Generator) Generate() (UUID, error) {
 	var uuid UUID
 
 	// Generate 16 random bytes
-	_, err := rand.Read(uuid[:])
-	if err != nil {
-		return uuid, fmt.Errorf("failed to generate random bytes: %w", err)
+	if err := g.read(uuid[:]); err != nil {
+		return UUID{}, err
 	}
 
 	// Set version (4) in the version field (bits 4-7 of byte 6)
@@ -83,6 +179,8 @@ func GenerateUUIDv4Batch(count int) ([]string, error) {
 
 // UUIDv7Generator generates time-ordered UUID v7
 type UUIDv7Generator struct {
+	instrumented
+	randomSource
 	mu            sync.Mutex
 	lastTimestamp int64
 	sequence      uint16
@@ -100,43 +198,73 @@ var globalUUIDv7Generator = &UUIDv7Generator{}
 // - 2 bits: Variant (10)
 // - 62 bits: Random data
 func NewUUIDv7() (UUID, error) {
-	return globalUUIDv7Generator.Generate()
+	return NewUUIDv7Context(context.Background())
+}
+
+// NewUUIDv7Context generates a new UUID v7 like NewUUIDv7, but stops waiting
+// for the next millisecond when ctx is canceled or its deadline would expire.
+func NewUUIDv7Context(ctx context.Context) (UUID, error) {
+	if o := uuidv7OverrideFn.Load(); o != nil {
+		return o.fn()
+	}
+	return globalUUIDv7Generator.GenerateContext(ctx)
 }
 
 // Generate creates a new UUID v7
 func (g *UUIDv7Generator) Generate() (UUID, error) {
+	return g.GenerateContext(context.Background())
+}
+
+// GenerateContext creates a new UUID v7 like Generate, but stops waiting for the
+// next millisecond when ctx is canceled or its deadline would expire.
+// In that case a *WaitCanceledError wrapping ctx.Err() is returned.
+func (g *UUIDv7Generator) GenerateContext(ctx context.Context) (UUID, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
 	var uuid UUID
 
+	h := g.loadHooks()
+
 	// Get current timestamp in milliseconds
 	now := time.Now().UnixMilli()
+	if now < g.lastTimestamp {
+		regression := time.Duration(g.lastTimestamp-now) * time.Millisecond
+		h.observer.ClockRegression(GeneratorUUIDv7, regression)
+		h.log(ctx, slog.LevelWarn, "idgen: clock moved backwards",
+			slog.String("generator", GeneratorUUIDv7),
+			slog.Int64("delta_ms", regression.Milliseconds()),
+			slog.Int64("waited_ms", 0),
+		)
+	}
 
 	// Handle clock regression or same millisecond
+	sequence := uint16(0)
 	if now <= g.lastTimestamp {
-		g.sequence++
+		sequence = g.sequence + 1
 		// If sequence overflows, wait for next millisecond
-		if g.sequence > 0x0FFF { // 12 bits max
-			time.Sleep(time.Millisecond)
+		if sequence > 0x0FFF { // 12 bits max
+			h.observer.SequenceExhausted(GeneratorUUIDv7)
+			if err := sleepContext(ctx, time.Millisecond); err != nil {
+				return uuid, &WaitCanceledError{Reason: reasonSequenceExhausted, Err: err}
+			}
+			h.observer.Waited(GeneratorUUIDv7, time.Millisecond)
 			now = time.Now().UnixMilli()
-			g.sequence = 0
+			sequence = 0
 		}
-	} else {
-		g.sequence = 0
 	}
+
+	// Fill the random bytes directly into uuid[8:16].
+	// The state is only advanced once they were read, so a failure consumes nothing.
+	if err := g.read(uuid[8:]); err != nil {
+		return UUID{}, err
+	}
+	g.sequence = sequence
 	g.lastTimestamp = now
 
 	// Fill timestamp (48 bits) - bytes 0-5
 	binary.BigEndian.PutUint64(uuid[0:8], uint64(now)<<16)
 
-	// Generate random bytes for the rest
-	randomBytes := make([]byte, 10)
-	_, err := rand.Read(randomBytes)
-	if err != nil {
-		return uuid, fmt.Errorf("failed to generate random bytes: %w", err)
-	}
-
 	// Set version 7 and sequence in bytes 6-7
 	// Byte 6: version (4 bits) + sequence high (4 bits)
 	uuid[6] = 0x70 | byte((g.sequence>>8)&0x0F)
@@ -144,15 +272,65 @@ func (g *UUIDv7Generator) Generate() (UUID, error) {
 	// Byte 7: sequence low (8 bits)
 	uuid[7] = byte(g.sequence & 0xFF)
 
-	// Copy random bytes to uuid[8:16]
-	copy(uuid[8:], randomBytes[0:8])
-
 	// Set variant (RFC 4122) in byte 8
 	uuid[8] = (uuid[8] & 0x3f) | 0x80
 
+	h.observer.Generated(GeneratorUUIDv7, 1)
 	return uuid, nil
 }
 
+// SetObserver attaches an Observer that receives generation, wait and clock events.
+// Passing nil restores the default no-op observer.
+// It is safe to call while the generator is in use.
+func (g *UUIDv7Generator) SetObserver(o Observer) {
+	g.setObserver(o)
+}
+
+// SetLogger attaches a structured logger that records clock anomalies
+// with the fields delta_ms and waited_ms.
+// Passing nil disables logging, which is the default.
+// It is safe to call while the generator is in use.
+func (g *UUIDv7Generator) SetLogger(l *slog.Logger) {
+	g.setLogger(l)
+}
+
+// GenerateString creates a new UUID v7 in canonical form, implementing Generator
+func (g *UUIDv7Generator) GenerateString(ctx context.Context) (string, error) {
+	uuid, err := g.GenerateContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	return uuid.String(), nil
+}
+
+// SetEntropy sets the source of the random bits. Passing nil restores the shared
+// entropy buffers, which is the default. It is safe to call while the generator is in use.
+func (g *UUIDv7Generator) SetEntropy(r io.Reader) {
+	g.setEntropy(r)
+}
+
+// SetUUIDv4Entropy sets the source of random bytes behind NewUUIDv4 and GenerateUUIDv4.
+// Passing nil restores the shared entropy buffers.
+func SetUUIDv4Entropy(r io.Reader) {
+	globalUUIDv4Generator.SetEntropy(r)
+}
+
+// SetUUIDv7Entropy sets the source of random bits behind NewUUIDv7, GenerateUUIDv7 and NewTypeID.
+// Passing nil restores the shared entropy buffers.
+func SetUUIDv7Entropy(r io.Reader) {
+	globalUUIDv7Generator.SetEntropy(r)
+}
+
+// SetUUIDv7Logger attaches a structured logger to the generator behind NewUUIDv7 and GenerateUUIDv7.
+func SetUUIDv7Logger(l *slog.Logger) {
+	globalUUIDv7Generator.SetLogger(l)
+}
+
+// SetUUIDv7Observer attaches an Observer to the generator behind NewUUIDv7 and GenerateUUIDv7.
+func SetUUIDv7Observer(o Observer) {
+	globalUUIDv7Generator.SetObserver(o)
+}
+
 // GenerateUUIDv7 generates a new UUID v7 and returns it as a string
 func GenerateUUIDv7() (string, error) {
 	uuid, err := NewUUIDv7()
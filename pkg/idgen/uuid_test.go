@@ -1,6 +1,8 @@
 package idgen
 
 import (
+	"context"
+	"errors"
 	"regexp"
 	"strings"
 	"testing"
@@ -207,6 +209,7 @@ func TestUUIDv4Format(t *testing.T) {
 }
 
 func BenchmarkNewUUIDv4(b *testing.B) {
+	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		_, err := NewUUIDv4()
 		if err != nil {
@@ -476,7 +479,43 @@ func TestUUIDv7Format(t *testing.T) {
 	}
 }
 
+func TestUUIDv7GenerateContext(t *testing.T) {
+	uuid, err := NewUUIDv7Context(context.Background())
+	if err != nil {
+		t.Fatalf("NewUUIDv7Context() error = %v", err)
+	}
+	if version := (uuid[6] >> 4) & 0x0f; version != 7 {
+		t.Errorf("NewUUIDv7Context() version = %d, want 7", version)
+	}
+}
+
+func TestUUIDv7GenerateContextCanceled(t *testing.T) {
+	generator := &UUIDv7Generator{
+		lastTimestamp: time.Now().UnixMilli() + 1000,
+		sequence:      0x0FFF,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	uuid, err := generator.GenerateContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GenerateContext() error = %v, want context.Canceled", err)
+	}
+	var waitErr *WaitCanceledError
+	if !errors.As(err, &waitErr) {
+		t.Errorf("GenerateContext() error type = %T, want *WaitCanceledError", err)
+	}
+	if uuid != (UUID{}) {
+		t.Errorf("GenerateContext() returned partial UUID %s on error", uuid)
+	}
+	if generator.sequence != 0x0FFF {
+		t.Errorf("Canceled call modified generator state: sequence = %d", generator.sequence)
+	}
+}
+
 func BenchmarkNewUUIDv7(b *testing.B) {
+	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		_, err := NewUUIDv7()
 		if err != nil {